@@ -66,6 +66,8 @@
 package gosynthcalc
 
 import (
+	"io"
+
 	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
 	"github.com/Syrov-Egor/gosynthcalc/internal/chemreaction"
 )
@@ -119,6 +121,300 @@ const (
 
 type MethodResult = chemreaction.MethodResult
 
+// A label-amount pair used for parsed formulas, percents and oxide analyses.
+type Atom = chemformula.Atom
+
+// A struct for the recalculation of a microprobe oxide analysis (wt%) into
+// a mineral formula. It should be constructed with [NewMineralFormula] or
+// [NewMineralFormulaOnBasis] and can calculate atoms per formula unit on
+// a fixed-oxygen basis, Fe2+/Fe3+ partitioning by the Droop method and
+// site allocation for common mineral groups.
+type MineralFormula = chemformula.MineralFormula
+
+// A crystallographic site of a [MineralFormula] with its ideal capacity
+// and allocated cations.
+type Site = chemformula.Site
+
+// Mineral group defines the oxygen basis, the ideal number of cations
+// (used for Fe3+ estimation) and the site allocation scheme:
+//
+//   - Feldspar: 8 O, 5 cations, T and A sites
+//   - Pyroxene: 6 O, 4 cations, T, M1 and M2 sites
+//   - Garnet: 12 O, 8 cations, Z, Y and X sites
+//   - Spinel: 4 O, 3 cations, T and M sites
+type MineralGroup = chemformula.MineralGroup
+
+const (
+	Feldspar MineralGroup = chemformula.Feldspar
+	Pyroxene MineralGroup = chemformula.Pyroxene
+	Garnet   MineralGroup = chemformula.Garnet
+	Spinel   MineralGroup = chemformula.Spinel
+)
+
+// A struct for the glass batch calculation: masses of raw materials
+// required to obtain the target oxide composition of a glass.
+// It should be constructed with [NewGlassBatch]. Volatile elements
+// (C, H, N) of raw materials are considered to be lost on melting.
+type GlassBatch = chemformula.GlassBatch
+
+// Basis of a composition given in percents:
+//
+//   - MassBasis: weight percent (wt%)
+//   - MoleBasis: mole percent (mol% or at%)
+type Basis = chemformula.Basis
+
+const (
+	MassBasis Basis = chemformula.MassBasis
+	MoleBasis Basis = chemformula.MoleBasis
+)
+
+// A struct for the Seger (unity molecular) formula of a ceramic glaze:
+// oxides grouped into fluxes (RO/R2O), amphoterics (R2O3) and
+// glass formers (RO2), normalized so the fluxes sum to 1.
+// It should be constructed with [NewSegerFormula] or [NewSegerFormulaFromRecipe]
+// and can calculate the raw material recipe for the formula.
+type SegerFormula = chemformula.SegerFormula
+
+// Oxide group of the [SegerFormula]: Flux (RO/R2O), Amphoteric (R2O3) or GlassFormer (RO2).
+type SegerGroup = chemformula.SegerGroup
+
+const (
+	Flux        SegerGroup = chemformula.Flux
+	Amphoteric  SegerGroup = chemformula.Amphoteric
+	GlassFormer SegerGroup = chemformula.GlassFormer
+)
+
+// A struct for the alloy batch calculation: masses of pure metals, master alloys
+// or compounds required to obtain the target alloy composition.
+// It should be constructed with [NewAlloyBatch] and reports the elements
+// which composition cannot be reached with given sources.
+type AlloyBatch = chemformula.AlloyBatch
+
+// A struct for a physical mixture of chemical formulas with known mass or mole fractions
+// (an ore, a flux blend or an impure reagent). It should be constructed with
+// [NewChemicalMixture] and can calculate the same properties as [ChemicalFormula]
+// for one averaged formula unit of the mixture. Mixture can be used as a compound
+// of [ChemicalReaction] via its averaged formula:
+//
+//	limestone, _ := g.NewChemicalMixture([]g.Atom{{"CaCO3", 98}, {"MgCO3", 2}}, g.MassBasis)
+//	reaction, _ := g.NewChemicalReaction(limestone.Formula() + "=CaO+MgO+CO2")
+type ChemicalMixture = chemformula.ChemicalMixture
+
+// A struct for the theoretical capacity of a battery electrode material.
+// It should be constructed with [NewElectrodeCapacity]. Gravimetric capacity (mAh/g)
+// is n*F/(3.6*M), volumetric capacity (mAh/cm3) is gravimetric capacity times density.
+// RangeCapacity gives the capacity of a partial (de)intercalation, e.g. x = 0 - 0.7 of Li
+// in "LiNi0.8Co0.1Mn0.1O2", relative to the mass of the given formula.
+type ElectrodeCapacity = chemformula.ElectrodeCapacity
+
+// Lattice parameters of a unit cell: a, b, c (in Å) and α, β, γ (in degrees).
+type UnitCell = chemformula.UnitCell
+
+// A struct for the X-ray (crystallographic) density of a compound, Z*M/(N_A*V).
+// It should be constructed with [NewXRayDensity] or [NewXRayDensityFromVolume].
+// Density is in g/cm3. FormulaUnits calculates Z from a measured density and
+// RelativeDensity gives the measured density as a percent of the X-ray density.
+type XRayDensity = chemformula.XRayDensity
+
+// A struct for a solution of a solute (optionally weighed as a hydrate, e.g. "CuSO4*5H2O")
+// in a solvent. It should be constructed with [NewSolution]. The concentration refers
+// to the anhydrous solute and can be converted to any [ConcentrationUnit]; conversions
+// between volumetric (mol/L, g/L) and other units require the density of the solution.
+type Solution = chemformula.Solution
+
+// A struct for planning dilutions from stock solutions (solute formulas with
+// concentrations in mol/L). It should be constructed with [NewStockPlanner].
+// Volumes are in mL; a volume of a stock less than the minimum (pipette) volume
+// is an error, use SerialDilution instead. MixMetals mixes single- or mixed-metal
+// stocks so the metal ratios of the solution match a target formula (e.g. for co-precipitation).
+type StockPlanner = chemformula.StockPlanner
+
+// A step of a dilution plan: volumes of the sources (stocks or the solution of the
+// previous step) and of the solvent, and the resulting concentrations (mol/L).
+type DilutionStep = chemformula.DilutionStep
+
+// Units of solution concentration:
+//
+//   - Molarity: mol of solute per L of solution
+//   - Molality: mol of solute per kg of solvent
+//   - MassFraction: g of solute per g of solution
+//   - MoleFraction: mol of solute per mol of solute and solvent
+//   - PPM: mg of solute per kg of solution
+//   - GramsPerLiter: g of solute per L of solution
+type ConcentrationUnit = chemformula.ConcentrationUnit
+
+const (
+	Molarity      ConcentrationUnit = chemformula.Molarity
+	Molality      ConcentrationUnit = chemformula.Molality
+	MassFraction  ConcentrationUnit = chemformula.MassFraction
+	MoleFraction  ConcentrationUnit = chemformula.MoleFraction
+	PPM           ConcentrationUnit = chemformula.PPM
+	GramsPerLiter ConcentrationUnit = chemformula.GramsPerLiter
+)
+
+// A struct for a chemical formula with symbolic subscripts, such as
+// "Ba1-xSrxTiO3" or "YBa2Cu3O7-δ". It should be constructed with [NewFormulaTemplate].
+// A subscript expression consists of numbers and single lowercase (Latin or Greek)
+// variables joined by "-", e.g. "1-x", "7-δ", "2x" or "3-x-y".
+// Elements with zero subscript are dropped from the evaluated formula.
+// Note that a variable immediately after an element symbol is read as a part
+// of the symbol if it forms a valid element ("Nd" is neodymium, not N with d subscript).
+type FormulaTemplate = chemformula.FormulaTemplate
+
+// A struct for a chemical reaction with symbolic subscripts and coefficients,
+// such as "BaCO3+SrCO3+TiO2=Ba1-xSrxTiO3+CO2". It should be constructed with
+// [NewReactionTemplate] and evaluated to [ChemicalReaction] for given variable values.
+type ReactionTemplate = chemreaction.ReactionTemplate
+
+// A struct for a composition series of a [ReactionTemplate] over values of one
+// variable (e.g. x = 0, 0.05, ..., 0.5 for "Ba1-xSrxTiO3"). It should be
+// constructed with [NewReactionSeries]. Compounds that vanish at the end members
+// (e.g. SrCO3 at x = 0) are dropped from the corresponding reaction and get zero mass.
+// The result table can be exported as text, CSV or JSON via Output.
+type ReactionSeries = chemreaction.ReactionSeries
+
+// A row of [ReactionSeries] with the balanced reaction and masses aligned
+// to the compounds of the template. Error is set if the member can't be calculated.
+type SeriesRow = chemreaction.SeriesRow
+
+// An auxiliary reagent of a synthesis that is not a part of the balanced reaction,
+// such as a chelating agent or a polyesterification agent (see
+// [ChemicalReaction.SetAuxiliaryReagents]). Ratio is moles of the reagent per mole
+// of metal ions in the reactants or, if Base is set, per mole of the previous
+// auxiliary reagent with that formula.
+type AuxiliaryReagent = chemreaction.AuxiliaryReagent
+
+// A struct for the (co-)precipitation of metal salts (nitrates, sulfates, chlorides,
+// bromides, iodides, perchlorates and acetates) with a precipitant (NaOH, KOH, LiOH,
+// NH4OH, Na2CO3, K2CO3, (NH4)2CO3, C2H2O4, (NH4)2C2O4 or Na2C2O4). It should be
+// constructed with [NewPrecipitation]. The reaction to the precipitate and
+// by-product salts (with H2O, NH3 or CO2 if required, see Spectators) is generated and
+// balanced automatically; the precipitant mass is given with the excess.
+type Precipitation = chemreaction.Precipitation
+
+// A struct for titration calculations based on a balanced reaction of the analyte
+// with the titrant. It should be constructed with [NewTitration]. Volumes are in mL
+// and concentrations in mol/L (normalities in eq/L).
+type Titration = chemreaction.Titration
+
+// Thermochemical data of a compound in a phase: standard enthalpy of formation Hf
+// (kJ/mol) and entropy S (J/(mol*K)) at 298.15 K, the heat capacity coefficients
+// Cp = a + b*T + c/T^2 + d*T^2 (J/(mol*K)) and the optional melting temperature Tm (K),
+// enthalpy of melting Hm (kJ/mol) and heat capacity of the liquid CpLiquid.
+type ThermoData = chemreaction.ThermoData
+
+// A table of [ThermoData] keyed by formula and phase. It should be constructed with
+// [NewThermoTable] or read from a local JSON or CSV file with [LoadThermoTable].
+type ThermoTable = chemreaction.ThermoTable
+
+// A struct for the thermochemistry of a balanced [ChemicalReaction]: ΔH°, ΔS° and ΔG°(T)
+// for the reaction as written by its coefficients. It should be constructed with [NewReactionThermo].
+type ReactionThermo = chemreaction.ReactionThermo
+
+// A diluent of a self-propagating reaction, given either by Moles per reaction
+// as written or by MassFraction in the whole mixture.
+type Diluent = chemreaction.Diluent
+
+// A struct for the adiabatic combustion temperature of a balanced [ChemicalReaction]
+// with optional diluents. It should be constructed with [NewAdiabatic].
+type Adiabatic = chemreaction.Adiabatic
+
+// The equilibrium composition found by [Equilibrium]: moles of every species
+// and the total Gibbs energy (kJ) at the temperature (K) and pressure (bar).
+type EquilibriumComposition = chemreaction.EquilibriumComposition
+
+// A struct for the equilibrium composition by Gibbs energy minimization. Element totals are
+// taken from the reactants of the initial [ChemicalReaction]. Gases (phase "g") form an
+// ideal mixture, other phases are pure. It should be constructed with [NewEquilibrium].
+type Equilibrium = chemreaction.Equilibrium
+
+// A struct for the basis of independent reactions among species without a reactant/product split.
+// Each basis reaction forms one dependent species from the independent ones with integer
+// coefficients. It should be constructed with [NewReactionBasis].
+type ReactionBasis = chemreaction.ReactionBasis
+
+// The result of [CompleteReaction]: the balanced reaction and the spectator
+// species added to its reactants and products.
+type Completion = chemreaction.Completion
+
+// Criterion of ranking the reactions suggested by [PrecursorSuggester]:
+//   - FewestPrecursors: the smallest number of precursors
+//   - LeastGas: the smallest mass of gaseous by-products per target mass
+//   - LowestCost: the lowest cost of the precursors (reactions with missing prices go last)
+//   - PreferredAnions: the best family of the worst precursor in the anion preference list
+type Criterion = chemreaction.Criterion
+
+const (
+	FewestPrecursors Criterion = chemreaction.FewestPrecursors
+	LeastGas         Criterion = chemreaction.LeastGas
+	LowestCost       Criterion = chemreaction.LowestCost
+	PreferredAnions  Criterion = chemreaction.PreferredAnions
+)
+
+// A candidate reaction for the target found by [PrecursorSuggester] with the precursors,
+// their anion families, masses, gas evolved (g) and cost of the precursors.
+type Suggestion = chemreaction.Suggestion
+
+// A struct for the enumeration and ranking of balanced reactions for a target compound from
+// an inventory of precursors. Gaseous by-products and O2 are added as in [CompleteReaction].
+// It should be constructed with [NewPrecursorSuggester].
+type PrecursorSuggester = chemreaction.PrecursorSuggester
+
+// A row of [PrecursorComparison]: the masses of the precursors to weigh, the batch mass,
+// the mass of gaseous by-products, the weight loss (%) of the batch and the cost of the precursors.
+type ComparisonRow = chemreaction.ComparisonRow
+
+// A struct for the side-by-side comparison of alternative precursor sets for one target.
+// Gaseous by-products and O2 are added as in [CompleteReaction].
+// It should be constructed with [NewPrecursorComparison].
+type PrecursorComparison = chemreaction.PrecursorComparison
+
+// A price of a reagent of some grade: Price in the catalogue currency per Unit ("g" or "kg").
+// If PackSize (g) is set, the reagent is sold only in whole packs.
+type PriceEntry = chemreaction.PriceEntry
+
+// A catalogue of [PriceEntry] keyed by formula, grade and pack size. Formulas are matched by
+// their parsed composition, so "Ba(CO3)" finds the price of "BaCO3". It should be constructed
+// with [NewPriceCatalogue] or read from a local JSON or CSV file with [LoadPriceCatalogue].
+type PriceCatalogue = chemreaction.PriceCatalogue
+
+// The cost of a reactant found by [ChemicalReaction.Cost]: the cost of the mass used
+// and the number and cost of the packs to buy.
+type CompoundCost = chemreaction.CompoundCost
+
+// The cost of a batch of the target mass found by [ChemicalReaction.Cost], per gram of the
+// product and per mole of the target. Reactants without a price are listed in Missing,
+// then only the costs of the priced compounds are given and the totals are left at zero.
+type ReactionCost = chemreaction.ReactionCost
+
+// A struct for Faraday's law calculations of electrolysis and electrodeposition.
+// It should be constructed with [NewElectrolysis]. Electrons can be written in the
+// reaction as the "e" species (e.g. "CuSO4+2e=Cu+SO4", then the rest of the reaction
+// is checked to be balanced as written) or given as the number of electrons per formula
+// unit of the target for an ordinary reaction (e.g. 2 for Cu in "CuSO4+H2O=Cu+H2SO4+O2").
+// Charge is in C (or Ah), current in A and time in s.
+type Electrolysis = chemreaction.Electrolysis
+
+// A struct for the solution-combustion synthesis of an oxide from metal nitrates
+// and a fuel (glycine, urea, citric acid, ...) by Jain's propellant chemistry method.
+// It should be constructed with [NewCombustion]. Valences are C +4, H +1, O -2, N 0,
+// the valence of a metal is its oxidation state in a nitrate precursor (hydrates allowed),
+// other precursors require SetValences. The fuel amount is phi times the stoichiometric one;
+// the reaction is balanced with CO2, H2O, N2 and O2 (a reactant for fuel-rich mixtures).
+type Combustion = chemreaction.Combustion
+
+// A struct for a grid over a composition simplex of one mixed site, such as
+// "(Fe,Co,Ni)3O4" with 5% steps, for combinatorial synthesis. It should be
+// constructed with [NewCompositionGrid]. For every grid point the reaction of the
+// precursors to the target and by-products is balanced and the masses are calculated;
+// for precursors added as solutions (see SetConcentrations) the dispensing volumes (in mL)
+// are calculated as well. Regions of the simplex can be excluded with SetBounds and Exclude.
+type CompositionGrid = chemreaction.CompositionGrid
+
+// A point of [CompositionGrid]. Fractions are aligned to the elements of the mixed site,
+// Masses to the precursors, target and by-products, Volumes to the precursors.
+type GridPoint = chemreaction.GridPoint
+
 // Builder function to create [ChemicalFormula] object.
 func NewChemicalFormula(formula string, precision ...uint) (*ChemicalFormula, error) {
 	return chemformula.NewChemicalFormula(formula, precision...)
@@ -128,3 +424,255 @@ func NewChemicalFormula(formula string, precision ...uint) (*ChemicalFormula, er
 func NewChemicalReaction(reaction string, options ...ReactionOptions) (*ChemicalReaction, error) {
 	return chemreaction.NewChemicalReaction(reaction, options...)
 }
+
+// Builder function to create [FormulaTemplate] object.
+func NewFormulaTemplate(template string) (*FormulaTemplate, error) {
+	return chemformula.NewFormulaTemplate(template)
+}
+
+// Builder function to create [ReactionTemplate] object.
+func NewReactionTemplate(template string) (*ReactionTemplate, error) {
+	return chemreaction.NewReactionTemplate(template)
+}
+
+// Builder function to create [ReactionSeries] object. The options are applied
+// to every member of the series (the Target index refers to the template compounds).
+func NewReactionSeries(template *ReactionTemplate, variable string, values []float64, options ...ReactionOptions) (*ReactionSeries, error) {
+	return chemreaction.NewReactionSeries(template, variable, values, options...)
+}
+
+// Returns values from start to stop (inclusive) with the given step,
+// rounded to avoid floating point drift (e.g. 0, 0.05, ..., 0.5).
+func SeriesRange(start, stop, step float64) []float64 {
+	return chemreaction.SeriesRange(start, stop, step)
+}
+
+// Builder function to create [Electrolysis] object. Efficiency is the current
+// efficiency in range (0, 1], the target mass of the options is the mass of the
+// product to be obtained (deposited). If electrons are written in the reaction,
+// the Rmode of the options is ignored and the reaction is always checked as written.
+func NewElectrolysis(reaction string, electrons float64, efficiency float64, options ...ReactionOptions) (*Electrolysis, error) {
+	return chemreaction.NewElectrolysis(reaction, electrons, efficiency, options...)
+}
+
+// Builder function to create [Combustion] object. Phi is the fuel-to-oxidizer
+// equivalence ratio, the target mass of the options refers to the target oxide.
+func NewCombustion(target string, precursors []string, fuel string, phi float64, options ...ReactionOptions) (*Combustion, error) {
+	return chemreaction.NewCombustion(target, precursors, fuel, phi, options...)
+}
+
+// Builder function to create [Precipitation] object. Excess is a fraction of the
+// stoichiometric precipitant amount (0.1 for 10%). The precipitate formula may be
+// empty for a single precursor, then it is derived from the metal valence in the salt.
+// The target mass of the options refers to the precipitate, the Target index is ignored.
+func NewPrecipitation(precursors []string, precipitant string, precipitate string, excess float64, options ...ReactionOptions) (*Precipitation, error) {
+	return chemreaction.NewPrecipitation(precursors, precipitant, precipitate, excess, options...)
+}
+
+// Builder function to create [Titration] object. Analyte and titrant are the
+// indexes of compounds in the reaction with the same convention as the Target of
+// [ReactionOptions], titrantEquivalents is the number of equivalents per mole of
+// titrant (e.g. 1 for NaOH, 5 for KMnO4 in acidic media).
+func NewTitration(reaction string, analyte int, titrant int, titrantEquivalents float64, options ...ReactionOptions) (*Titration, error) {
+	return chemreaction.NewTitration(reaction, analyte, titrant, titrantEquivalents, options...)
+}
+
+// Builder function to create [ThermoTable] object from a slice of [ThermoData].
+func NewThermoTable(data []ThermoData) (*ThermoTable, error) {
+	return chemreaction.NewThermoTable(data)
+}
+
+// Reads [ThermoTable] from a JSON array of objects with the keys
+// "formula", "phase", "hf", "s", "cp", "tm", "hm" and "cpl".
+func ReadThermoJSON(r io.Reader) (*ThermoTable, error) {
+	return chemreaction.ReadThermoJSON(r)
+}
+
+// Reads [ThermoTable] from CSV with the header "formula,phase,hf,s,a,b,c,d,tm,hm,cpl".
+// Heat capacity and melting columns are optional, cpl is a constant heat capacity of the liquid.
+func ReadThermoCSV(r io.Reader) (*ThermoTable, error) {
+	return chemreaction.ReadThermoCSV(r)
+}
+
+// Loads [ThermoTable] from a local .json or .csv file.
+func LoadThermoTable(path string) (*ThermoTable, error) {
+	return chemreaction.LoadThermoTable(path)
+}
+
+// Builder function to create [ReactionThermo] object. The phases map assigns
+// a phase to the formulas with several phases in the table.
+func NewReactionThermo(reaction *ChemicalReaction, table *ThermoTable, phases map[string]string) (*ReactionThermo, error) {
+	return chemreaction.NewReactionThermo(reaction, table, phases)
+}
+
+// Builder function to create [Adiabatic] object. Reactants are taken at the initial
+// temperature (K); melting of the products is accounted by the Tm and Hm of [ThermoData].
+func NewAdiabatic(reaction *ChemicalReaction, table *ThermoTable, phases map[string]string, initial float64, diluents ...Diluent) (*Adiabatic, error) {
+	return chemreaction.NewAdiabatic(reaction, table, phases, initial, diluents...)
+}
+
+// Builder function to create [Equilibrium] object. Every species formula is taken
+// in all its phases from the table unless the phase is given in the phases map.
+func NewEquilibrium(initial *ChemicalReaction, species []string, table *ThermoTable, phases map[string]string) (*Equilibrium, error) {
+	return chemreaction.NewEquilibrium(initial, species, table, phases)
+}
+
+// Builder function to create [ReactionBasis] object. The options are used for
+// the basis reactions, which are always created in the Force mode.
+func NewReactionBasis(species []string, options ...ReactionOptions) (*ReactionBasis, error) {
+	return chemreaction.NewReactionBasis(species, options...)
+}
+
+// Completes the reaction with the minimal set (up to 3) of spectator species added to either side
+// so that it can be balanced with positive coefficients. If spectators is nil, the common gaseous
+// species O2, CO2, H2O, NH3, NO2, N2 and H2 are tried. The reaction is always balanced in the Balance mode.
+func CompleteReaction(reaction string, spectators []string, options ...ReactionOptions) (Completion, error) {
+	return chemreaction.CompleteReaction(reaction, spectators, options...)
+}
+
+// Builder function to create [PrecursorSuggester] object. Only the inventory precursors without
+// elements foreign to the target (except O, H, C and N) are used. The options are used for the
+// suggested reactions with the target as the first product.
+func NewPrecursorSuggester(target string, inventory []string, options ...ReactionOptions) (*PrecursorSuggester, error) {
+	return chemreaction.NewPrecursorSuggester(target, inventory, options...)
+}
+
+// Builder function to create [PrecursorComparison] object. The options are used
+// for the reactions of every set with the target as the first product.
+func NewPrecursorComparison(target string, precursorSets [][]string, options ...ReactionOptions) (*PrecursorComparison, error) {
+	return chemreaction.NewPrecursorComparison(target, precursorSets, options...)
+}
+
+// Builder function to create [PriceCatalogue] object from a slice of [PriceEntry].
+func NewPriceCatalogue(entries []PriceEntry) (*PriceCatalogue, error) {
+	return chemreaction.NewPriceCatalogue(entries)
+}
+
+// Reads [PriceCatalogue] from a JSON array of objects with the keys
+// "formula", "grade", "price", "unit", "pack" and "currency".
+func ReadPriceJSON(r io.Reader) (*PriceCatalogue, error) {
+	return chemreaction.ReadPriceJSON(r)
+}
+
+// Reads [PriceCatalogue] from CSV with the header "formula,grade,price,unit,pack,currency".
+// Only the formula and price columns are required, the unit defaults to "g".
+func ReadPriceCSV(r io.Reader) (*PriceCatalogue, error) {
+	return chemreaction.ReadPriceCSV(r)
+}
+
+// Loads [PriceCatalogue] from a local .json or .csv file.
+func LoadPriceCatalogue(path string) (*PriceCatalogue, error) {
+	return chemreaction.LoadPriceCatalogue(path)
+}
+
+// Builder function to create [CompositionGrid] object. The target mass of the options
+// refers to the grid formula, the Target index is ignored.
+func NewCompositionGrid(formula string, step float64, precursors []string, byproducts []string, options ...ReactionOptions) (*CompositionGrid, error) {
+	return chemreaction.NewCompositionGrid(formula, step, precursors, byproducts, options...)
+}
+
+// Builder function to create [MineralFormula] object for one of the
+// predefined mineral groups.
+func NewMineralFormula(oxides []Atom, group MineralGroup, precision ...uint) (*MineralFormula, error) {
+	return chemformula.NewMineralFormula(oxides, group, precision...)
+}
+
+// Builder function to create [MineralFormula] object on an arbitrary basis
+// of oxygens. If cations > 0, Fe3+ is estimated by charge balance (Droop method),
+// otherwise the iron is split as given in the analysis (FeO and Fe2O3).
+func NewMineralFormulaOnBasis(oxides []Atom, oxygens float64, cations float64, precision ...uint) (*MineralFormula, error) {
+	return chemformula.NewMineralFormulaOnBasis(oxides, oxygens, cations, precision...)
+}
+
+// Builder function to create [GlassBatch] object. The composition is a slice
+// of oxides (e.g. "SiO2", "Na2O") with their percents in the given basis,
+// materials are raw material formulas (e.g. "Na2CO3", "H3BO3") and glassMass
+// is the desired mass of glass (in grams).
+func NewGlassBatch(composition []Atom, basis Basis, materials []string, glassMass float64, precision ...uint) (*GlassBatch, error) {
+	return chemformula.NewGlassBatch(composition, basis, materials, glassMass, precision...)
+}
+
+// Builder function to create [SegerFormula] object from oxide moles.
+func NewSegerFormula(oxides []Atom, precision ...uint) (*SegerFormula, error) {
+	return chemformula.NewSegerFormula(oxides, precision...)
+}
+
+// Builder function to create [SegerFormula] object from a raw material recipe
+// (formulas with masses in grams or parts). Elements are converted to the common glaze
+// oxides (e.g. MnO, CoO, Fe2O3, P2O5), which can be overridden by custom inOxides the same
+// way as in [ChemicalFormula.OxidePercent]. Raw materials with non-oxide anions
+// (F, Cl, Br, I, S) are rejected.
+func NewSegerFormulaFromRecipe(recipe []Atom, inOxides []string, precision ...uint) (*SegerFormula, error) {
+	return chemformula.NewSegerFormulaFromRecipe(recipe, inOxides, precision...)
+}
+
+// Builder function to create [AlloyBatch] object. The composition is a formula-like
+// string with amounts in the given basis (e.g. "Ti50Ni49.5Fe0.5" in at%),
+// ingotMass is the desired mass of the ingot (in grams) and sources are the
+// formulas of metals, master alloys or compounds (pure elements of the composition by default).
+// The source masses always sum to ingotMass; if the composition is unreachable, the deviations
+// larger than 0.5*10^-precision (in % of the basis) are reported by Unreachable.
+func NewAlloyBatch(composition string, basis Basis, ingotMass float64, sources []string, precision ...uint) (*AlloyBatch, error) {
+	return chemformula.NewAlloyBatch(composition, basis, ingotMass, sources, precision...)
+}
+
+// Builder function to create [ElectrodeCapacity] object. Electrons is the number
+// of electrons exchanged per formula unit, density (in g/cm3) may be 0 if unknown.
+func NewElectrodeCapacity(formula string, electrons float64, density float64, precision ...uint) (*ElectrodeCapacity, error) {
+	return chemformula.NewElectrodeCapacity(formula, electrons, density, precision...)
+}
+
+// Builder function to create [Solution] object. Density of the solution (in g/mL)
+// may be 0 if unknown.
+func NewSolution(solute string, solvent string, concentration float64, unit ConcentrationUnit, density float64, precision ...uint) (*Solution, error) {
+	return chemformula.NewSolution(solute, solvent, concentration, unit, density, precision...)
+}
+
+// Builder function to create [StockPlanner] object. The stocks are solute formulas
+// with concentrations in mol/L, minVolume is the minimum volume to pipette (in mL).
+func NewStockPlanner(stocks []Atom, minVolume float64, precision ...uint) (*StockPlanner, error) {
+	return chemformula.NewStockPlanner(stocks, minVolume, precision...)
+}
+
+// Builder function to create [XRayDensity] object from the lattice parameters.
+// Z is the number of formula units per cell (0 if unknown).
+func NewXRayDensity(formula string, z float64, cell UnitCell, precision ...uint) (*XRayDensity, error) {
+	return chemformula.NewXRayDensity(formula, z, cell, precision...)
+}
+
+// Builder function to create [XRayDensity] object from the cell volume (in Å3).
+func NewXRayDensityFromVolume(formula string, z float64, volume float64, precision ...uint) (*XRayDensity, error) {
+	return chemformula.NewXRayDensityFromVolume(formula, z, volume, precision...)
+}
+
+// Builder function to create [ChemicalMixture] object. The components are formulas
+// with their fractions (or percents) in the given basis.
+func NewChemicalMixture(components []Atom, basis Basis, precision ...uint) (*ChemicalMixture, error) {
+	return chemformula.NewChemicalMixture(components, basis, precision...)
+}
+
+// Returns true if the element is a metal (metalloids B, Si, As and Te are not).
+func IsMetal(element string) bool {
+	return chemformula.IsMetal(element)
+}
+
+// Returns the auxiliary reagents of the Pechini method: citric acid with caRatio
+// moles per mole of metal ions and ethylene glycol with egRatio moles per mole of citric acid.
+func PechiniReagents(caRatio float64, egRatio float64) []AuxiliaryReagent {
+	return chemreaction.PechiniReagents(caRatio, egRatio)
+}
+
+// Returns the auxiliary reagents of the EDTA-citrate method: EDTA and citric acid
+// with edtaRatio and caRatio moles per mole of metal ions and ammonia with
+// nh3Ratio moles per mole of EDTA.
+func EDTACitrateReagents(edtaRatio float64, caRatio float64, nh3Ratio float64) []AuxiliaryReagent {
+	return chemreaction.EDTACitrateReagents(edtaRatio, caRatio, nh3Ratio)
+}
+
+// Returns the gravimetric factor to convert the mass of the weighed form into the
+// mass of the sought substance, e.g. 0.6994 for "Fe2O3" to "Fe".
+// The stoichiometry is taken from the first element (except O and H) of the
+// sought substance present in the weighed form.
+func GravimetricFactor(weighed string, sought string, precision ...uint) (float64, error) {
+	return chemformula.GravimetricFactor(weighed, sought, precision...)
+}
@@ -0,0 +1,287 @@
+package chemformula
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+	"gonum.org/v1/gonum/mat"
+)
+
+type AlloyBatch struct {
+	composition []Atom
+	basis       Basis
+	ingotMass   float64
+	sources     []string
+	precision   uint
+	tolerance   float64
+	elements    []string
+	solution    *[]float64
+	achieved    *[]Atom
+}
+
+func NewAlloyBatch(composition string, basis Basis, ingotMass float64, sources []string, precision ...uint) (*AlloyBatch, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	newComposition := strings.Replace(composition, " ", "", -1)
+	validator := formulaValidator{formula: newComposition}
+	err := validator.validate()
+	if err != nil {
+		return nil, err
+	}
+	if ingotMass <= 0 {
+		return nil, fmt.Errorf("Ingot mass %v should be > 0", ingotMass)
+	}
+
+	parsed := chemicalFormulaParser{}.parse(newComposition)
+	elements := []string{}
+	for _, atom := range parsed {
+		elements = append(elements, atom.Label)
+	}
+
+	newSources := []string{}
+	if len(sources) == 0 {
+		newSources = append(newSources, elements...)
+	}
+	for _, source := range sources {
+		source = strings.Replace(source, " ", "", -1)
+		validator := formulaValidator{formula: source}
+		err := validator.validate()
+		if err != nil {
+			return nil, err
+		}
+		for _, atom := range (chemicalFormulaParser{}).parse(source) {
+			if !slices.Contains(elements, atom.Label) {
+				elements = append(elements, atom.Label)
+			}
+		}
+		newSources = append(newSources, source)
+	}
+
+	return &AlloyBatch{
+		composition: parsed,
+		basis:       basis,
+		ingotMass:   ingotMass,
+		sources:     newSources,
+		precision:   prec,
+		tolerance:   0.5 * math.Pow(10, -float64(prec)),
+		elements:    elements,
+	}, nil
+}
+
+func (a *AlloyBatch) Sources() []string {
+	return a.sources
+}
+
+func (a *AlloyBatch) Composition() []Atom {
+	return roundAtomS(a.percents(a.composition, a.basis), a.precision)
+}
+
+func (a *AlloyBatch) percents(amounts []Atom, basis Basis) []Atom {
+	if basis == MassBasis {
+		return toMassPercent(amounts, MassBasis, nil)
+	}
+	return molarMass{amounts}.atomicPercent()
+}
+
+func (a *AlloyBatch) targetMassFractions() []float64 {
+	var percents []Atom
+	if a.basis == MassBasis {
+		percents = toMassPercent(a.composition, MassBasis, nil)
+	} else {
+		percents = molarMass{a.composition}.massPercent()
+	}
+	fractions := make([]float64, len(a.elements))
+	for _, atom := range percents {
+		fractions[slices.Index(a.elements, atom.Label)] = atom.Amount / 100
+	}
+	return fractions
+}
+
+func (a *AlloyBatch) yields() *mat.Dense {
+	yields := mat.NewDense(len(a.elements), len(a.sources), nil)
+	for j, source := range a.sources {
+		for _, atom := range (molarMass{(chemicalFormulaParser{}).parse(source)}).massPercent() {
+			yields.Set(slices.Index(a.elements, atom.Label), j, atom.Amount/100)
+		}
+	}
+	return yields
+}
+
+func (a *AlloyBatch) solve() ([]float64, error) {
+	if a.solution == nil {
+		fractions := a.targetMassFractions()
+		target := make([]float64, len(fractions))
+		for i, fr := range fractions {
+			target[i] = fr * a.ingotMass
+		}
+		solution, _, err := utils.NNLS(a.yields(), target, 1e-12)
+		if err != nil {
+			return nil, err
+		}
+		total := utils.SumFloatS(solution)
+		if total == 0 {
+			return nil, fmt.Errorf("Composition can't be obtained from the sources %v", a.sources)
+		}
+		for i := range solution {
+			solution[i] *= a.ingotMass / total
+		}
+		a.solution = &solution
+	}
+	return *a.solution, nil
+}
+
+func (a *AlloyBatch) Masses() ([]float64, error) {
+	solution, err := a.solve()
+	if err != nil {
+		return nil, err
+	}
+	return utils.RoundFloatS(solution, a.precision), nil
+}
+
+func (a *AlloyBatch) IngotMass() (float64, error) {
+	masses, err := a.Masses()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(utils.SumFloatS(masses), a.precision), nil
+}
+
+func (a *AlloyBatch) achievedComposition() ([]Atom, error) {
+	if a.achieved == nil {
+		masses, err := a.solve()
+		if err != nil {
+			return nil, err
+		}
+		var elMasses mat.VecDense
+		elMasses.MulVec(a.yields(), mat.NewVecDense(len(masses), masses))
+		amounts := make([]Atom, len(a.elements))
+		for i, el := range a.elements {
+			amounts[i] = Atom{Label: el, Amount: elMasses.AtVec(i)}
+			if a.basis == MoleBasis {
+				amounts[i].Amount /= periodicTable[el].weight
+			}
+		}
+		achieved := a.percents(amounts, a.basis)
+		a.achieved = &achieved
+	}
+	return *a.achieved, nil
+}
+
+func (a *AlloyBatch) AchievedComposition() ([]Atom, error) {
+	achieved, err := a.achievedComposition()
+	if err != nil {
+		return nil, err
+	}
+	return roundAtomS(achieved, a.precision), nil
+}
+
+func (a *AlloyBatch) deviations() ([]Atom, error) {
+	achieved, err := a.achievedComposition()
+	if err != nil {
+		return nil, err
+	}
+	target := a.percents(a.composition, a.basis)
+	deviations := make([]Atom, len(achieved))
+	for i, atom := range achieved {
+		deviations[i] = atom
+		for _, t := range target {
+			if t.Label == atom.Label {
+				deviations[i].Amount -= t.Amount
+			}
+		}
+	}
+	return deviations, nil
+}
+
+func (a *AlloyBatch) Deviations() ([]Atom, error) {
+	deviations, err := a.deviations()
+	if err != nil {
+		return nil, err
+	}
+	return roundAtomS(deviations, a.precision), nil
+}
+
+func (a *AlloyBatch) Unreachable() ([]Atom, error) {
+	deviations, err := a.deviations()
+	if err != nil {
+		return nil, err
+	}
+	unreachable := []Atom{}
+	for _, dev := range deviations {
+		if math.Abs(dev.Amount) > a.tolerance {
+			unreachable = append(unreachable, dev)
+		}
+	}
+	return roundAtomS(unreachable, a.precision), nil
+}
+
+func (a *AlloyBatch) Output(printPrecision ...uint) (abOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	masses, err := a.Masses()
+	if err != nil {
+		return abOutput{}, err
+	}
+	ingot, err := a.IngotMass()
+	if err != nil {
+		return abOutput{}, err
+	}
+	achieved, err := a.AchievedComposition()
+	if err != nil {
+		return abOutput{}, err
+	}
+	unreachable, err := a.Unreachable()
+	if err != nil {
+		return abOutput{}, err
+	}
+
+	return abOutput{
+		Basis:       a.basis.String(),
+		Composition: roundAtomS(a.Composition(), pPrecision),
+		Achieved:    roundAtomS(achieved, pPrecision),
+		Unreachable: roundAtomS(unreachable, pPrecision),
+		IngotMass:   utils.RoundFloat(ingot, pPrecision),
+		Sources:     a.sources,
+		Masses:      utils.RoundFloatS(masses, pPrecision),
+	}, nil
+}
+
+type abOutput struct {
+	Basis       string
+	Composition []Atom
+	Achieved    []Atom
+	Unreachable []Atom
+	IngotMass   float64
+	Sources     []string
+	Masses      []float64
+}
+
+func (o abOutput) String() string {
+	out := fmt.Sprintf("target composition (%s): %v\n", o.Basis, o.Composition) +
+		fmt.Sprintf("achieved composition (%s): %v\n", o.Basis, o.Achieved) +
+		fmt.Sprintln("unreachable:", o.Unreachable) +
+		fmt.Sprintln("ingot mass:", o.IngotMass)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	for i, source := range o.Sources {
+		fmt.Fprintf(w, "%s\tm = %v\tg\n", source, o.Masses[i])
+	}
+
+	w.Flush()
+	return out + strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,105 @@
+package chemformula
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAlloyBatch_Masses(t *testing.T) {
+	tests := []struct {
+		name        string
+		composition string
+		basis       Basis
+		mass        float64
+		sources     []string
+		expected    []float64
+	}{
+		{
+			name:        "pure metals at%",
+			composition: "Ti50Ni49.5Fe0.5",
+			basis:       MoleBasis,
+			mass:        20,
+			sources:     nil,
+			expected:    []float64{8.9864, 10.9087, 0.1048},
+		},
+		{
+			name:        "master alloy at%",
+			composition: "Ti50Ni49.5Fe0.5",
+			basis:       MoleBasis,
+			mass:        20,
+			sources:     []string{"Ti", "NiTi", "Fe"},
+			expected:    []float64{0.0899, 19.8053, 0.1048},
+		},
+		{
+			name:        "compound source wt%",
+			composition: "Al95Cu5",
+			basis:       MassBasis,
+			mass:        10,
+			sources:     []string{"Al", "CuAl2"},
+			expected:    []float64{9.0754, 0.9246},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAlloyBatch(tt.composition, tt.basis, tt.mass, tt.sources, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := a.Masses()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Masses() = %v, expected %v", result, tt.expected)
+			}
+			unreachable, _ := a.Unreachable()
+			if len(unreachable) != 0 {
+				t.Errorf("Unreachable() = %v, expected none", unreachable)
+			}
+		})
+	}
+}
+
+func TestAlloyBatch_Unreachable(t *testing.T) {
+	tests := []struct {
+		name        string
+		composition string
+		basis       Basis
+		sources     []string
+		precision   uint
+		expected    int
+	}{
+		{"reachable at low precision", "Al92.5Cu7.5", MoleBasis, nil, 0, 0},
+		{"reachable at low precision wt%", "Ti89.5Al6.5V4", MassBasis, nil, 0, 0},
+		{"fixed ratio source", "Al95Cu5", MassBasis, []string{"CuAl2"}, 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAlloyBatch(tt.composition, tt.basis, 10, tt.sources, tt.precision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			unreachable, err := a.Unreachable()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(unreachable) != tt.expected {
+				t.Errorf("Unreachable() = %v, expected %d elements", unreachable, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAlloyBatch_Output(t *testing.T) {
+	a, _ := NewAlloyBatch("Ti50Ni50", MoleBasis, 10, []string{"Ti", "NiB"})
+	got, _ := a.Output()
+	expected := `target composition (mol%): ['Ti': 50 'Ni': 50]
+achieved composition (mol%): ['Ti': 34.0787 'Ni': 32.9606 'B': 32.9606]
+unreachable: ['Ti': -15.9213 'Ni': -17.0394 'B': 32.9606]
+ingot mass: 10
+Ti   m = 4.1591  g
+NiB  m = 5.8409  g`
+	if got.String() != expected {
+		t.Errorf("Output() expected %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,135 @@
+package chemformula
+
+import (
+	"fmt"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+const FaradayConstant = 96485.33212
+
+var ionCharges = map[string]float64{
+	"H":  1,
+	"Li": 1,
+	"Na": 1,
+	"K":  1,
+	"Mg": 2,
+	"Ca": 2,
+	"Zn": 2,
+	"Al": 3,
+}
+
+type ElectrodeCapacity struct {
+	formula   *ChemicalFormula
+	electrons float64
+	density   float64
+	precision uint
+}
+
+func NewElectrodeCapacity(formula string, electrons float64, density float64, precision ...uint) (*ElectrodeCapacity, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	form, err := NewChemicalFormula(formula, prec)
+	if err != nil {
+		return nil, err
+	}
+	if electrons <= 0 {
+		return nil, fmt.Errorf("Number of exchanged electrons %v should be > 0", electrons)
+	}
+	if density < 0 {
+		return nil, fmt.Errorf("Negative density %v", density)
+	}
+
+	return &ElectrodeCapacity{
+		formula:   form,
+		electrons: electrons,
+		density:   density,
+		precision: prec,
+	}, nil
+}
+
+func (e *ElectrodeCapacity) Formula() string {
+	return e.formula.Formula()
+}
+
+func (e *ElectrodeCapacity) MolarMass() float64 {
+	return e.formula.MolarMass()
+}
+
+func (e *ElectrodeCapacity) capacity(electrons float64) float64 {
+	return electrons * FaradayConstant / (3.6 * molarMass{e.formula.ParsedFormula()}.molarMass())
+}
+
+func (e *ElectrodeCapacity) Gravimetric() float64 {
+	return utils.RoundFloat(e.capacity(e.electrons), e.precision)
+}
+
+func (e *ElectrodeCapacity) Volumetric() (float64, error) {
+	if e.density == 0 {
+		return 0, fmt.Errorf("Density of %s is not set", e.formula.Formula())
+	}
+	return utils.RoundFloat(e.capacity(e.electrons)*e.density, e.precision), nil
+}
+
+func (e *ElectrodeCapacity) RangeCapacity(ion string, from float64, to float64) (float64, error) {
+	charge, ok := ionCharges[ion]
+	if !ok {
+		return 0, fmt.Errorf("Unknown charge carrier %s", ion)
+	}
+	if from < 0 || to < from {
+		return 0, fmt.Errorf("Invalid range %v - %v of %s extraction", from, to, ion)
+	}
+	amount := 0.0
+	for _, atom := range e.formula.ParsedFormula() {
+		if atom.Label == ion {
+			amount += atom.Amount
+		}
+	}
+	if to > amount {
+		return 0, fmt.Errorf("Can't extract %v %s from %s containing %v %s", to, ion, e.formula.Formula(), amount, ion)
+	}
+	return utils.RoundFloat(e.capacity((to-from)*charge), e.precision), nil
+}
+
+func (e *ElectrodeCapacity) Output(printPrecision ...uint) ecOutput {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	volumetric, _ := e.Volumetric()
+	return ecOutput{
+		Formula:     e.formula.Formula(),
+		MolarMass:   utils.RoundFloat(e.MolarMass(), pPrecision),
+		Electrons:   e.electrons,
+		Density:     e.density,
+		Gravimetric: utils.RoundFloat(e.Gravimetric(), pPrecision),
+		Volumetric:  utils.RoundFloat(volumetric, pPrecision),
+	}
+}
+
+type ecOutput struct {
+	Formula     string
+	MolarMass   float64
+	Electrons   float64
+	Density     float64
+	Gravimetric float64
+	Volumetric  float64
+}
+
+func (o ecOutput) String() string {
+	out := fmt.Sprintln("formula:", o.Formula) +
+		fmt.Sprintln("molar mass:", o.MolarMass) +
+		fmt.Sprintln("electrons per formula unit:", o.Electrons) +
+		fmt.Sprintf("gravimetric capacity: %v mAh/g", o.Gravimetric)
+	if o.Density > 0 {
+		out += fmt.Sprintf("\ndensity: %v g/cm3\n", o.Density) +
+			fmt.Sprintf("volumetric capacity: %v mAh/cm3", o.Volumetric)
+	}
+	return out
+}
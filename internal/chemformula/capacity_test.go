@@ -0,0 +1,74 @@
+package chemformula
+
+import (
+	"math"
+	"testing"
+)
+
+func TestElectrodeCapacity(t *testing.T) {
+	tests := []struct {
+		formula     string
+		electrons   float64
+		density     float64
+		gravimetric float64
+		volumetric  float64
+	}{
+		{"LiCoO2", 1, 5.06, 273.8, 1385.4},
+		{"LiFePO4", 1, 3.6, 169.9, 611.7},
+		{"Li4Ti5O12", 3, 3.48, 175.1, 609.4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.formula, func(t *testing.T) {
+			c, err := NewElectrodeCapacity(tt.formula, tt.electrons, tt.density)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Gravimetric(); math.Abs(got-tt.gravimetric) > 0.1 {
+				t.Errorf("Gravimetric() = %v, expected %v", got, tt.gravimetric)
+			}
+			got, err := c.Volumetric()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.volumetric) > 0.5 {
+				t.Errorf("Volumetric() = %v, expected %v", got, tt.volumetric)
+			}
+		})
+	}
+}
+
+func TestElectrodeCapacity_RangeCapacity(t *testing.T) {
+	c, err := NewElectrodeCapacity("LiNi0.8Co0.1Mn0.1O2", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.RangeCapacity("Li", 0, 0.7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got-0.7*c.Gravimetric()) > 1e-6 {
+		t.Errorf("RangeCapacity() = %v, expected %v", got, 0.7*c.Gravimetric())
+	}
+	if _, err := c.RangeCapacity("Li", 0, 1.2); err == nil {
+		t.Error("expected error for range beyond Li content")
+	}
+	if _, err := c.RangeCapacity("Xx", 0, 0.5); err == nil {
+		t.Error("expected error for unknown ion")
+	}
+	if _, err := c.Volumetric(); err == nil {
+		t.Error("expected error for missing density")
+	}
+}
+
+func TestElectrodeCapacityOutput(t *testing.T) {
+	c, _ := NewElectrodeCapacity("LiFePO4", 1, 3.6)
+	expected := `formula: LiFePO4
+molar mass: 157.7548
+electrons per formula unit: 1
+gravimetric capacity: 169.8933 mAh/g
+density: 3.6 g/cm3
+volumetric capacity: 611.616 mAh/cm3`
+	if got := c.Output().String(); got != expected {
+		t.Errorf("Output() expected %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,242 @@
+package chemformula
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+	"gonum.org/v1/gonum/mat"
+)
+
+type DilutionStep struct {
+	Sources       []string
+	Volumes       []float64
+	SolventVolume float64
+	Solutes       []Atom
+}
+
+func (s DilutionStep) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	for i, source := range s.Sources {
+		fmt.Fprintf(w, "%s\tV = %v\tmL\n", source, s.Volumes[i])
+	}
+	fmt.Fprintf(w, "solvent\tV = %v\tmL\n", s.SolventVolume)
+
+	w.Flush()
+	return buf.String() + fmt.Sprint("result (mol/L): ", s.Solutes)
+}
+
+type StockPlanner struct {
+	stocks    []ChemicalFormula
+	molarity  []float64
+	minVolume float64
+	precision uint
+}
+
+func NewStockPlanner(stocks []Atom, minVolume float64, precision ...uint) (*StockPlanner, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	if len(stocks) == 0 {
+		return nil, fmt.Errorf("No stock solutions")
+	}
+	if minVolume < 0 {
+		return nil, fmt.Errorf("Negative minimum volume %v", minVolume)
+	}
+	formulas := make([]ChemicalFormula, len(stocks))
+	molarity := make([]float64, len(stocks))
+	for i, stock := range stocks {
+		if stock.Amount <= 0 {
+			return nil, fmt.Errorf("Concentration %v of stock '%s' should be > 0", stock.Amount, stock.Label)
+		}
+		f, err := NewChemicalFormula(stock.Label, prec)
+		if err != nil {
+			return nil, err
+		}
+		formulas[i] = *f
+		molarity[i] = stock.Amount
+	}
+
+	return &StockPlanner{
+		stocks:    formulas,
+		molarity:  molarity,
+		minVolume: minVolume,
+		precision: prec,
+	}, nil
+}
+
+func (p *StockPlanner) Stocks() []string {
+	stocks := make([]string, len(p.stocks))
+	for i, stock := range p.stocks {
+		stocks[i] = stock.Formula()
+	}
+	return stocks
+}
+
+func (p *StockPlanner) stockIndex(formula string) (int, error) {
+	best := -1
+	for i, stock := range p.stocks {
+		if stock.Formula() == strings.Replace(formula, " ", "", -1) && (best < 0 || p.molarity[i] > p.molarity[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return -1, fmt.Errorf("There is no stock solution of '%s'", formula)
+	}
+	return best, nil
+}
+
+func (p *StockPlanner) step(volumes []float64, volume float64) (DilutionStep, error) {
+	total := utils.SumFloatS(volumes)
+	if total > volume*(1+1e-9) {
+		return DilutionStep{}, fmt.Errorf("Stock solutions are too dilute: %v mL of stocks is required for %v mL", total, volume)
+	}
+	step := DilutionStep{
+		Sources:       p.Stocks(),
+		Volumes:       utils.RoundFloatS(volumes, p.precision),
+		SolventVolume: utils.RoundFloat(math.Max(volume-total, 0), p.precision),
+	}
+	for i, v := range volumes {
+		if v == 0 {
+			continue
+		}
+		if v < p.minVolume {
+			return DilutionStep{}, fmt.Errorf("Volume %v mL of stock '%s' is less than the minimum volume %v mL, use serial dilution",
+				utils.RoundFloat(v, p.precision), p.stocks[i].Formula(), p.minVolume)
+		}
+		step.Solutes = append(step.Solutes, Atom{
+			Label:  p.stocks[i].Formula(),
+			Amount: utils.RoundFloat(p.molarity[i]*v/volume, p.precision),
+		})
+	}
+	return step, nil
+}
+
+func (p *StockPlanner) Prepare(targets []Atom, volume float64) (DilutionStep, error) {
+	if volume <= 0 {
+		return DilutionStep{}, fmt.Errorf("Volume %v should be > 0", volume)
+	}
+	volumes := make([]float64, len(p.stocks))
+	for _, target := range targets {
+		if target.Amount < 0 {
+			return DilutionStep{}, fmt.Errorf("Negative concentration %v of '%s'", target.Amount, target.Label)
+		}
+		i, err := p.stockIndex(target.Label)
+		if err != nil {
+			return DilutionStep{}, err
+		}
+		volumes[i] += target.Amount * volume / p.molarity[i]
+	}
+	return p.step(volumes, volume)
+}
+
+func (p *StockPlanner) SerialDilution(stock string, concentration float64, volume float64) ([]DilutionStep, error) {
+	if volume <= 0 {
+		return nil, fmt.Errorf("Volume %v should be > 0", volume)
+	}
+	if concentration <= 0 {
+		return nil, fmt.Errorf("Concentration %v should be > 0", concentration)
+	}
+	i, err := p.stockIndex(stock)
+	if err != nil {
+		return nil, err
+	}
+	factor := p.molarity[i] / concentration
+	if factor < 1 {
+		return nil, fmt.Errorf("Stock solution of '%s' is too dilute for %v mol/L", stock, concentration)
+	}
+
+	steps := 1
+	if p.minVolume > 0 && volume/factor < p.minVolume {
+		maxFactor := volume / p.minVolume
+		if maxFactor <= 1 {
+			return nil, fmt.Errorf("Volume %v mL should be greater than the minimum volume %v mL", volume, p.minVolume)
+		}
+		steps = int(math.Ceil(math.Log(factor)/math.Log(maxFactor) - 1e-9))
+	}
+	stepFactor := math.Pow(factor, 1/float64(steps))
+
+	plan := make([]DilutionStep, steps)
+	source := p.stocks[i].Formula()
+	c := p.molarity[i]
+	for j := range plan {
+		transfer := volume / stepFactor
+		c /= stepFactor
+		plan[j] = DilutionStep{
+			Sources:       []string{source},
+			Volumes:       []float64{utils.RoundFloat(transfer, p.precision)},
+			SolventVolume: utils.RoundFloat(volume-transfer, p.precision),
+			Solutes:       []Atom{{Label: p.stocks[i].Formula(), Amount: utils.RoundFloat(c, p.precision)}},
+		}
+		source = fmt.Sprintf("step %d", j+1)
+	}
+	return plan, nil
+}
+
+func (p *StockPlanner) MixMetals(target string, metalConcentration float64, volume float64) (DilutionStep, error) {
+	if volume <= 0 {
+		return DilutionStep{}, fmt.Errorf("Volume %v should be > 0", volume)
+	}
+	if metalConcentration <= 0 {
+		return DilutionStep{}, fmt.Errorf("Metal concentration %v should be > 0", metalConcentration)
+	}
+	form, err := NewChemicalFormula(target, p.precision)
+	if err != nil {
+		return DilutionStep{}, err
+	}
+
+	metals := []Atom{}
+	for _, atom := range form.ParsedFormula() {
+		if IsMetal(atom.Label) && atom.Amount > 0 {
+			metals = append(metals, atom)
+		}
+	}
+	if len(metals) == 0 {
+		return DilutionStep{}, fmt.Errorf("There are no metals in '%s'", target)
+	}
+	sum := 0.0
+	for _, m := range metals {
+		sum += m.Amount
+	}
+
+	labels := []string{}
+	for _, m := range metals {
+		labels = append(labels, m.Label)
+	}
+	for _, stock := range p.stocks {
+		for _, atom := range stock.ParsedFormula() {
+			if IsMetal(atom.Label) && !slices.Contains(labels, atom.Label) {
+				labels = append(labels, atom.Label)
+			}
+		}
+	}
+
+	moles := make([]float64, len(labels))
+	for i, m := range metals {
+		moles[i] = metalConcentration * m.Amount / sum * volume
+	}
+	a := mat.NewDense(len(labels), len(p.stocks), nil)
+	for j, stock := range p.stocks {
+		for _, atom := range stock.ParsedFormula() {
+			if i := slices.Index(labels, atom.Label); i >= 0 {
+				a.Set(i, j, a.At(i, j)+p.molarity[j]*atom.Amount)
+			}
+		}
+	}
+	volumes, residual, err := utils.NNLS(a, moles, 1e-12)
+	if err != nil {
+		return DilutionStep{}, err
+	}
+	if residual > 1e-6*utils.SumFloatS(moles) {
+		return DilutionStep{}, fmt.Errorf("Metal ratios of '%s' can't be obtained from the stocks %v", target, p.Stocks())
+	}
+	return p.step(volumes, volume)
+}
@@ -0,0 +1,78 @@
+package chemformula
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestStockPlanner_Prepare(t *testing.T) {
+	p, err := NewStockPlanner([]Atom{{"NiSO4", 1}, {"CoSO4", 0.5}}, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step, err := p.Prepare([]Atom{{"NiSO4", 0.1}, {"CoSO4", 0.05}}, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(step.Volumes, []float64{5, 5}) || step.SolventVolume != 40 {
+		t.Errorf("Prepare() = %v, %v, expected [5 5], 40", step.Volumes, step.SolventVolume)
+	}
+
+	if _, err := p.Prepare([]Atom{{"NiSO4", 0.00001}}, 10); err == nil {
+		t.Error("expected error for volume below minimum")
+	}
+	if _, err := p.Prepare([]Atom{{"CoSO4", 1}}, 10); err == nil {
+		t.Error("expected error for too dilute stock")
+	}
+	if _, err := p.Prepare([]Atom{{"MnSO4", 0.1}}, 10); err == nil {
+		t.Error("expected error for missing stock")
+	}
+}
+
+func TestStockPlanner_SerialDilution(t *testing.T) {
+	p, _ := NewStockPlanner([]Atom{{"NiSO4", 1}}, 0.1)
+	steps, err := p.SerialDilution("NiSO4", 1e-5, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 3 {
+		t.Fatalf("len(SerialDilution()) = %d, expected 3", len(steps))
+	}
+	for i, step := range steps {
+		if step.Volumes[0] < 0.1 {
+			t.Errorf("step %d volume %v is less than minimum", i, step.Volumes[0])
+		}
+		if math.Abs(step.Volumes[0]+step.SolventVolume-10) > 1e-6 {
+			t.Errorf("step %d total volume %v, expected 10", i, step.Volumes[0]+step.SolventVolume)
+		}
+	}
+	if got := steps[2].Solutes[0].Amount; math.Abs(got-1e-5) > 1e-8 {
+		t.Errorf("final concentration = %v, expected 1e-5", got)
+	}
+	if steps[1].Sources[0] != "step 1" {
+		t.Errorf("source of step 2 = %s, expected step 1", steps[1].Sources[0])
+	}
+}
+
+func TestStockPlanner_MixMetals(t *testing.T) {
+	p, _ := NewStockPlanner([]Atom{{"Ni(NO3)2", 2}, {"Co(NO3)2", 1}, {"Mn(NO3)2", 1}}, 0.01)
+	step, err := p.MixMetals("LiNi0.8Co0.1Mn0.1O2", 1, 100)
+	if err == nil {
+		t.Fatalf("expected error for missing Li stock, got %v", step)
+	}
+
+	step, err = p.MixMetals("Ni0.8Co0.1Mn0.1(OH)2", 1, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{40, 10, 10}
+	for i := range expected {
+		if math.Abs(step.Volumes[i]-expected[i]) > 1e-6 {
+			t.Errorf("MixMetals() volumes = %v, expected %v", step.Volumes, expected)
+		}
+	}
+	if math.Abs(step.SolventVolume-40) > 1e-6 {
+		t.Errorf("MixMetals() solvent = %v, expected 40", step.SolventVolume)
+	}
+}
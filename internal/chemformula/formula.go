@@ -88,6 +88,10 @@ func (c *ChemicalFormula) OxidePercent(inOxides ...string) ([]Atom, error) {
 	return *c.oxidePercent, nil
 }
 
+func (c *ChemicalFormula) SegerFormula(inOxides ...string) (*SegerFormula, error) {
+	return NewSegerFormulaFromRecipe([]Atom{{Label: c.formula, Amount: c.MolarMass()}}, inOxides, c.precision)
+}
+
 func (c *ChemicalFormula) Output(printPrecision ...uint) cfOutput {
 	var pPrecision uint
 	if printPrecision == nil {
@@ -0,0 +1,323 @@
+package chemformula
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+	"gonum.org/v1/gonum/mat"
+)
+
+type Basis int
+
+const (
+	MassBasis Basis = iota
+	MoleBasis
+)
+
+func (b Basis) String() string {
+	return [...]string{"wt%", "mol%"}[b]
+}
+
+var volatileElements = []string{"C", "H", "N"}
+
+type oxideMap struct {
+	oxides []string
+	parsed [][]Atom
+	metals map[string]int
+}
+
+func newOxideMap(oxides []string) (*oxideMap, error) {
+	m := &oxideMap{
+		oxides: oxides,
+		parsed: make([][]Atom, len(oxides)),
+		metals: make(map[string]int),
+	}
+	for i, ox := range oxides {
+		parsed, err := parseOxide(ox)
+		if err != nil {
+			return nil, err
+		}
+		metal := parsed[0].Label
+		if _, ok := m.metals[metal]; ok {
+			return nil, fmt.Errorf("Element %s is present in more than one oxide", metal)
+		}
+		m.metals[metal] = i
+		m.parsed[i] = parsed
+	}
+	return m, nil
+}
+
+func (m *oxideMap) molarMasses() []float64 {
+	masses := make([]float64, len(m.parsed))
+	for i, parsed := range m.parsed {
+		masses[i] = molarMass{parsed}.molarMass()
+	}
+	return masses
+}
+
+func (m *oxideMap) moleYields(material string) ([]float64, error) {
+	validator := formulaValidator{formula: material}
+	err := validator.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	yields := make([]float64, len(m.oxides))
+	for _, atom := range (chemicalFormulaParser{}).parse(material) {
+		i, ok := m.metals[atom.Label]
+		switch {
+		case ok:
+			yields[i] += atom.Amount / m.parsed[i][0].Amount
+		case atom.Label == "O" || slices.Contains(volatileElements, atom.Label):
+		default:
+			return nil, fmt.Errorf("Element %s of raw material '%s' is not in the oxide composition", atom.Label, material)
+		}
+	}
+	return yields, nil
+}
+
+func (m *oxideMap) massYields(materials []string) (*mat.Dense, error) {
+	oxMasses := m.molarMasses()
+	yields := mat.NewDense(len(m.oxides), len(materials), nil)
+	for j, material := range materials {
+		moles, err := m.moleYields(material)
+		if err != nil {
+			return nil, err
+		}
+		matMass := molarMass{(chemicalFormulaParser{}).parse(material)}.molarMass()
+		for i, mol := range moles {
+			yields.Set(i, j, mol*oxMasses[i]/matMass)
+		}
+	}
+
+	rows, _ := yields.Dims()
+	for i := range rows {
+		if mat.Norm(yields.RowView(i), 1) == 0 {
+			return nil, fmt.Errorf("Oxide %s can't be obtained from the raw materials %v", m.oxides[i], materials)
+		}
+	}
+	return yields, nil
+}
+
+func toMassPercent(composition []Atom, basis Basis, molarMasses []float64) []Atom {
+	amounts := make([]float64, len(composition))
+	for i, atom := range composition {
+		amounts[i] = atom.Amount
+		if basis == MoleBasis {
+			amounts[i] *= molarMasses[i]
+		}
+	}
+	sum := utils.SumFloatS(amounts)
+	ret := make([]Atom, len(composition))
+	for i, atom := range composition {
+		ret[i] = Atom{Label: atom.Label, Amount: amounts[i] / sum * 100}
+	}
+	return ret
+}
+
+type GlassBatch struct {
+	composition []Atom
+	basis       Basis
+	materials   []string
+	glassMass   float64
+	precision   uint
+	oxides      *oxideMap
+	yields      *mat.Dense
+	masses      *[]float64
+}
+
+func NewGlassBatch(composition []Atom, basis Basis, materials []string, glassMass float64, precision ...uint) (*GlassBatch, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	switch {
+	case len(composition) == 0:
+		return nil, fmt.Errorf("Empty oxide composition")
+	case len(materials) == 0:
+		return nil, fmt.Errorf("Empty list of raw materials")
+	case glassMass <= 0:
+		return nil, fmt.Errorf("Glass mass %v should be > 0", glassMass)
+	}
+
+	labels := make([]string, len(composition))
+	for i, atom := range composition {
+		if atom.Amount < 0 {
+			return nil, fmt.Errorf("Negative amount %v of oxide '%s'", atom.Amount, atom.Label)
+		}
+		labels[i] = atom.Label
+	}
+	oxides, err := newOxideMap(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	newMaterials := make([]string, len(materials))
+	for i, material := range materials {
+		newMaterials[i] = strings.Replace(material, " ", "", -1)
+	}
+
+	return &GlassBatch{
+		composition: composition,
+		basis:       basis,
+		materials:   newMaterials,
+		glassMass:   glassMass,
+		precision:   prec,
+		oxides:      oxides,
+	}, nil
+}
+
+func (g *GlassBatch) Materials() []string {
+	return g.materials
+}
+
+func (g *GlassBatch) Composition() []Atom {
+	return roundAtomS(toMassPercent(g.composition, g.basis, g.oxides.molarMasses()), g.precision)
+}
+
+func (g *GlassBatch) Yields() (*mat.Dense, error) {
+	if g.yields == nil {
+		yields, err := g.oxides.massYields(g.materials)
+		if err != nil {
+			return nil, err
+		}
+		g.yields = yields
+	}
+	return g.yields, nil
+}
+
+func (g *GlassBatch) Masses() ([]float64, error) {
+	if g.masses == nil {
+		yields, err := g.Yields()
+		if err != nil {
+			return nil, err
+		}
+		target := toMassPercent(g.composition, g.basis, g.oxides.molarMasses())
+		oxMasses := make([]float64, len(target))
+		for i, atom := range target {
+			oxMasses[i] = atom.Amount / 100 * g.glassMass
+		}
+		masses, _, err := utils.NNLS(yields, oxMasses, 1e-12)
+		if err != nil {
+			return nil, err
+		}
+		masses = utils.RoundFloatS(masses, g.precision)
+		g.masses = &masses
+	}
+	return *g.masses, nil
+}
+
+func (g *GlassBatch) BatchMass() (float64, error) {
+	masses, err := g.Masses()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(utils.SumFloatS(masses), g.precision), nil
+}
+
+func (g *GlassBatch) oxideMasses() ([]float64, error) {
+	masses, err := g.Masses()
+	if err != nil {
+		return nil, err
+	}
+	yields, err := g.Yields()
+	if err != nil {
+		return nil, err
+	}
+	var oxMasses mat.VecDense
+	oxMasses.MulVec(yields, mat.NewVecDense(len(masses), masses))
+	return oxMasses.RawVector().Data, nil
+}
+
+func (g *GlassBatch) VolatileLoss() (float64, error) {
+	batch, err := g.BatchMass()
+	if err != nil {
+		return 0, err
+	}
+	oxMasses, err := g.oxideMasses()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(batch-utils.SumFloatS(oxMasses), g.precision), nil
+}
+
+func (g *GlassBatch) GlassComposition() ([]Atom, error) {
+	oxMasses, err := g.oxideMasses()
+	if err != nil {
+		return nil, err
+	}
+	achieved := make([]Atom, len(oxMasses))
+	for i, mass := range oxMasses {
+		achieved[i] = Atom{Label: g.oxides.oxides[i], Amount: mass}
+	}
+	return roundAtomS(toMassPercent(achieved, MassBasis, nil), g.precision), nil
+}
+
+func (g *GlassBatch) Output(printPrecision ...uint) (gbOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	masses, err := g.Masses()
+	if err != nil {
+		return gbOutput{}, err
+	}
+	batch, err := g.BatchMass()
+	if err != nil {
+		return gbOutput{}, err
+	}
+	loss, err := g.VolatileLoss()
+	if err != nil {
+		return gbOutput{}, err
+	}
+	achieved, err := g.GlassComposition()
+	if err != nil {
+		return gbOutput{}, err
+	}
+
+	return gbOutput{
+		Composition:      roundAtomS(g.Composition(), pPrecision),
+		GlassMass:        g.glassMass,
+		Materials:        g.materials,
+		Masses:           utils.RoundFloatS(masses, pPrecision),
+		BatchMass:        utils.RoundFloat(batch, pPrecision),
+		VolatileLoss:     utils.RoundFloat(loss, pPrecision),
+		GlassComposition: roundAtomS(achieved, pPrecision),
+	}, nil
+}
+
+type gbOutput struct {
+	Composition      []Atom
+	GlassMass        float64
+	Materials        []string
+	Masses           []float64
+	BatchMass        float64
+	VolatileLoss     float64
+	GlassComposition []Atom
+}
+
+func (o gbOutput) String() string {
+	out := fmt.Sprintln("target composition (wt%):", o.Composition) +
+		fmt.Sprintln("glass mass:", o.GlassMass) +
+		fmt.Sprintln("batch mass:", o.BatchMass) +
+		fmt.Sprintln("volatile loss:", o.VolatileLoss) +
+		fmt.Sprintln("glass composition (wt%):", o.GlassComposition)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	for i, material := range o.Materials {
+		fmt.Fprintf(w, "%s\tm = %v\tg\n", material, o.Masses[i])
+	}
+
+	w.Flush()
+	return out + strings.TrimSuffix(buf.String(), "\n")
+}
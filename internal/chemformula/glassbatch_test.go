@@ -0,0 +1,105 @@
+package chemformula
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGlassBatch_Masses(t *testing.T) {
+	tests := []struct {
+		name        string
+		composition []Atom
+		basis       Basis
+		materials   []string
+		glassMass   float64
+		expected    []float64
+	}{
+		{
+			name:        "soda-lime wt%",
+			composition: []Atom{{"SiO2", 75}, {"Na2O", 15}, {"CaO", 10}},
+			basis:       MassBasis,
+			materials:   []string{"SiO2", "Na2CO3", "CaCO3"},
+			glassMass:   100,
+			expected:    []float64{75, 25.651, 17.848},
+		},
+		{
+			name:        "borosilicate mol%",
+			composition: []Atom{{"SiO2", 70}, {"B2O3", 20}, {"Na2O", 10}},
+			basis:       MoleBasis,
+			materials:   []string{"SiO2", "H3BO3", "Na2CO3"},
+			glassMass:   50,
+			expected:    []float64{33.82, 19.888, 8.5227},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGlassBatch(tt.composition, tt.basis, tt.materials, tt.glassMass, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := g.Masses()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("Masses() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGlassBatch_Output(t *testing.T) {
+	g, _ := NewGlassBatch([]Atom{{"SiO2", 75}, {"Na2O", 15}, {"CaO", 10}}, MassBasis,
+		[]string{"SiO2", "Na2CO3", "CaCO3"}, 100)
+	got, _ := g.Output()
+	expected := `target composition (wt%): ['SiO2': 75 'Na2O': 15 'CaO': 10]
+glass mass: 100
+batch mass: 118.499
+volatile loss: 18.499
+glass composition (wt%): ['SiO2': 75 'Na2O': 15 'CaO': 10]
+SiO2    m = 75      g
+Na2CO3  m = 25.651  g
+CaCO3   m = 17.848  g`
+	if got.String() != expected {
+		t.Errorf("Output() expected %s, got %s", expected, got)
+	}
+}
+
+func TestGlassBatch_errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		composition []Atom
+		materials   []string
+		expected    string
+	}{
+		{
+			name:        "foreign element",
+			composition: []Atom{{"SiO2", 80}, {"B2O3", 20}},
+			materials:   []string{"SiO2", "H3BO3", "NaCl"},
+			expected:    "Element Na of raw material 'NaCl' is not in the oxide composition",
+		},
+		{
+			name:        "unreachable oxide",
+			composition: []Atom{{"SiO2", 80}, {"B2O3", 20}},
+			materials:   []string{"SiO2"},
+			expected:    "Oxide B2O3 can't be obtained from the raw materials [SiO2]",
+		},
+		{
+			name:        "duplicated metal",
+			composition: []Atom{{"FeO", 80}, {"Fe2O3", 20}},
+			materials:   []string{"Fe2O3"},
+			expected:    "Element Fe is present in more than one oxide",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGlassBatch(tt.composition, MassBasis, tt.materials, 10)
+			if err == nil {
+				_, err = g.Masses()
+			}
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("Masses() error = %v, expected %s", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package chemformula
+
+import (
+	"fmt"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+func GravimetricFactor(weighed string, sought string, precision ...uint) (float64, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	w, err := NewChemicalFormula(weighed)
+	if err != nil {
+		return 0, err
+	}
+	s, err := NewChemicalFormula(sought)
+	if err != nil {
+		return 0, err
+	}
+
+	amounts := func(atoms []Atom) map[string]float64 {
+		m := map[string]float64{}
+		for _, atom := range atoms {
+			m[atom.Label] += atom.Amount
+		}
+		return m
+	}
+	wAmounts := amounts(w.ParsedFormula())
+	key := ""
+	for _, atom := range s.ParsedFormula() {
+		if _, ok := wAmounts[atom.Label]; ok && atom.Label != "O" && atom.Label != "H" {
+			key = atom.Label
+			break
+		}
+	}
+	if key == "" {
+		return 0, fmt.Errorf("There is no common element (except O and H) in %s and %s", weighed, sought)
+	}
+
+	moles := wAmounts[key] / amounts(s.ParsedFormula())[key]
+	factor := moles * molarMass{s.ParsedFormula()}.molarMass() / molarMass{w.ParsedFormula()}.molarMass()
+	return utils.RoundFloat(factor, prec), nil
+}
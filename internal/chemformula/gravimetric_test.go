@@ -0,0 +1,33 @@
+package chemformula
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGravimetricFactor(t *testing.T) {
+	tests := []struct {
+		weighed  string
+		sought   string
+		expected float64
+	}{
+		{"BaSO4", "S", 0.13737},
+		{"BaSO4", "SO3", 0.34302},
+		{"Fe2O3", "Fe", 0.69943},
+		{"Fe2O3", "FeO", 0.89981},
+		{"Mg2P2O7", "P2O5", 0.63776},
+		{"AgCl", "Cl", 0.24736},
+	}
+	for _, tt := range tests {
+		got, err := GravimetricFactor(tt.weighed, tt.sought)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-tt.expected) > 1e-4 {
+			t.Errorf("GravimetricFactor(%s, %s) = %v, expected %v", tt.weighed, tt.sought, got, tt.expected)
+		}
+	}
+	if _, err := GravimetricFactor("BaSO4", "Fe"); err == nil {
+		t.Error("expected error for no common element")
+	}
+}
@@ -0,0 +1,400 @@
+package chemformula
+
+import (
+	"fmt"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+type MineralGroup int
+
+const (
+	Feldspar MineralGroup = iota
+	Pyroxene
+	Garnet
+	Spinel
+	NoGroup
+)
+
+func (g MineralGroup) String() string {
+	return [...]string{"Feldspar", "Pyroxene", "Garnet", "Spinel", "NoGroup"}[g]
+}
+
+const (
+	ferrous = "Fe2+"
+	ferric  = "Fe3+"
+)
+
+type mineralSite struct {
+	label    string
+	capacity float64
+	cations  []string
+}
+
+type mineralScheme struct {
+	oxygens float64
+	cations float64
+	sites   []mineralSite
+}
+
+var mineralSchemes = map[MineralGroup]mineralScheme{
+	Feldspar: {
+		oxygens: 8,
+		cations: 5,
+		sites: []mineralSite{
+			{"T", 4, []string{"Si", "Al", ferric, "Ti"}},
+			{"A", 1, []string{"Na", "K", "Ca", "Ba", "Sr", ferrous, "Mg", "Mn"}},
+		},
+	},
+	Pyroxene: {
+		oxygens: 6,
+		cations: 4,
+		sites: []mineralSite{
+			{"T", 2, []string{"Si", "Al", ferric}},
+			{"M1", 1, []string{"Al", ferric, "Ti", "Cr", "V", "Mg", ferrous, "Mn"}},
+			{"M2", 1, []string{"Mg", ferrous, "Mn", "Ca", "Na", "K"}},
+		},
+	},
+	Garnet: {
+		oxygens: 12,
+		cations: 8,
+		sites: []mineralSite{
+			{"Z", 3, []string{"Si", "Al", ferric}},
+			{"Y", 2, []string{"Al", "Ti", "Cr", "V", ferric}},
+			{"X", 3, []string{"Mg", ferrous, "Mn", "Ca", "Na"}},
+		},
+	},
+	Spinel: {
+		oxygens: 4,
+		cations: 3,
+		sites: []mineralSite{
+			{"T", 1, []string{"Mg", ferrous, "Mn", "Zn", "Ni", "Co"}},
+			{"M", 2, []string{"Al", "Cr", ferric, "Ti", "V", "Mg", ferrous}},
+		},
+	},
+}
+
+type Site struct {
+	Label    string
+	Capacity float64
+	Cations  []Atom
+}
+
+func (s Site) String() string {
+	return fmt.Sprintf("%s (%v): %v", s.Label, s.Capacity, s.Cations)
+}
+
+type mineralCation struct {
+	label   string
+	moles   float64
+	oxygens float64
+}
+
+type MineralFormula struct {
+	oxides    []Atom
+	group     MineralGroup
+	oxygens   float64
+	cations   float64
+	precision uint
+	apfu      *[]Atom
+	sites     *[]Site
+}
+
+func NewMineralFormula(oxides []Atom, group MineralGroup, precision ...uint) (*MineralFormula, error) {
+	scheme, ok := mineralSchemes[group]
+	if !ok {
+		return nil, fmt.Errorf("No site scheme for mineral group %d", group)
+	}
+	return newMineralFormula(oxides, group, scheme.oxygens, scheme.cations, precision...)
+}
+
+func NewMineralFormulaOnBasis(oxides []Atom, oxygens float64, cations float64, precision ...uint) (*MineralFormula, error) {
+	return newMineralFormula(oxides, NoGroup, oxygens, cations, precision...)
+}
+
+func newMineralFormula(oxides []Atom, group MineralGroup, oxygens float64, cations float64, precision ...uint) (*MineralFormula, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	switch {
+	case len(oxides) == 0:
+		return nil, fmt.Errorf("Empty oxide analysis")
+	case oxygens <= 0:
+		return nil, fmt.Errorf("Number of oxygens %v should be > 0", oxygens)
+	case cations < 0:
+		return nil, fmt.Errorf("Number of cations %v should be >= 0", cations)
+	}
+	for _, ox := range oxides {
+		if ox.Amount < 0 {
+			return nil, fmt.Errorf("Negative amount %v of oxide '%s'", ox.Amount, ox.Label)
+		}
+	}
+
+	return &MineralFormula{
+		oxides:    oxides,
+		group:     group,
+		oxygens:   oxygens,
+		cations:   cations,
+		precision: prec,
+	}, nil
+}
+
+func (m *MineralFormula) Oxides() []Atom {
+	return m.oxides
+}
+
+func (m *MineralFormula) Group() MineralGroup {
+	return m.group
+}
+
+func (m *MineralFormula) cationMoles() ([]mineralCation, error) {
+	res := []mineralCation{}
+	index := make(map[string]int)
+	for _, ox := range m.oxides {
+		parsed, err := parseOxide(ox.Label)
+		if err != nil {
+			return nil, err
+		}
+
+		moles := ox.Amount / molarMass{parsed}.molarMass()
+		label := parsed[0].Label
+		if label == "Fe" {
+			if parsed[1].Amount/parsed[0].Amount > 1 {
+				label = ferric
+			} else {
+				label = ferrous
+			}
+		}
+
+		i, ok := index[label]
+		if !ok {
+			index[label] = len(res)
+			res = append(res, mineralCation{label: label})
+			i = len(res) - 1
+		}
+		res[i].moles += moles * parsed[0].Amount
+		res[i].oxygens += moles * parsed[1].Amount
+	}
+	return res, nil
+}
+
+func (m *MineralFormula) droop(cations []mineralCation) []mineralCation {
+	feTotal := 0.0
+	ret := []mineralCation{}
+	for _, cat := range cations {
+		if cat.label == ferrous || cat.label == ferric {
+			feTotal += cat.moles
+		} else {
+			ret = append(ret, cat)
+		}
+	}
+	if feTotal == 0 {
+		return m.normalize(cations)
+	}
+	ret = append(ret, mineralCation{label: ferrous, moles: feTotal, oxygens: feTotal})
+
+	ret = m.normalize(ret)
+	sum := 0.0
+	for _, cat := range ret {
+		sum += cat.moles
+	}
+
+	fe3 := 2 * m.oxygens * (1 - m.cations/sum)
+	if fe3 <= 0 {
+		return ret
+	}
+	for i := range ret {
+		ret[i].moles *= m.cations / sum
+		ret[i].oxygens *= m.cations / sum
+	}
+	feTotal = ret[len(ret)-1].moles
+	fe3 = min(fe3, feTotal)
+	ret[len(ret)-1] = mineralCation{label: ferrous, moles: feTotal - fe3, oxygens: feTotal - fe3}
+	ret = append(ret, mineralCation{label: ferric, moles: fe3, oxygens: 1.5 * fe3})
+
+	return ret
+}
+
+func (m *MineralFormula) normalize(cations []mineralCation) []mineralCation {
+	oxygenSum := 0.0
+	for _, cat := range cations {
+		oxygenSum += cat.oxygens
+	}
+	ret := make([]mineralCation, len(cations))
+	for i, cat := range cations {
+		ret[i] = mineralCation{
+			label:   cat.label,
+			moles:   cat.moles * m.oxygens / oxygenSum,
+			oxygens: cat.oxygens * m.oxygens / oxygenSum,
+		}
+	}
+	return ret
+}
+
+func (m *MineralFormula) calculateAPFU() ([]Atom, error) {
+	cations, err := m.cationMoles()
+	if err != nil {
+		return nil, err
+	}
+
+	oxygenSum := 0.0
+	for _, cat := range cations {
+		oxygenSum += cat.oxygens
+	}
+	if oxygenSum == 0 {
+		return nil, fmt.Errorf("Oxide analysis sums to zero")
+	}
+
+	if m.cations > 0 {
+		cations = m.droop(cations)
+	} else {
+		cations = m.normalize(cations)
+	}
+
+	ret := make([]Atom, len(cations))
+	for i, cat := range cations {
+		ret[i] = Atom{Label: cat.label, Amount: cat.moles}
+	}
+	return ret, nil
+}
+
+func (m *MineralFormula) APFU() ([]Atom, error) {
+	if m.apfu == nil {
+		apfu, err := m.calculateAPFU()
+		if err != nil {
+			return nil, err
+		}
+		apfu = roundAtomS(apfu, m.precision)
+		m.apfu = &apfu
+	}
+	return *m.apfu, nil
+}
+
+func (m *MineralFormula) FerricIron() (float64, float64, error) {
+	apfu, err := m.APFU()
+	if err != nil {
+		return 0, 0, err
+	}
+	var fe2, fe3 float64
+	for _, atom := range apfu {
+		switch atom.Label {
+		case ferrous:
+			fe2 = atom.Amount
+		case ferric:
+			fe3 = atom.Amount
+		}
+	}
+	return fe2, fe3, nil
+}
+
+func (m *MineralFormula) Sites() ([]Site, error) {
+	if m.sites == nil {
+		scheme, ok := mineralSchemes[m.group]
+		if !ok {
+			return nil, fmt.Errorf("No site scheme for mineral group %s", m.group)
+		}
+		apfu, err := m.APFU()
+		if err != nil {
+			return nil, err
+		}
+
+		left := make(map[string]float64)
+		for _, atom := range apfu {
+			left[atom.Label] += atom.Amount
+		}
+
+		sites := make([]Site, len(scheme.sites))
+		for i, site := range scheme.sites {
+			filled := 0.0
+			occupancy := []Atom{}
+			for _, cat := range site.cations {
+				amount := min(left[cat], site.capacity-filled)
+				if utils.RoundFloat(amount, m.precision) <= 0 {
+					continue
+				}
+				left[cat] -= amount
+				filled += amount
+				occupancy = append(occupancy, Atom{Label: cat, Amount: amount})
+			}
+			sites[i] = Site{
+				Label:    site.label,
+				Capacity: site.capacity,
+				Cations:  roundAtomS(occupancy, m.precision),
+			}
+		}
+
+		unassigned := []Atom{}
+		for _, atom := range apfu {
+			if amount := utils.RoundFloat(left[atom.Label], m.precision); amount > 0 {
+				unassigned = append(unassigned, Atom{Label: atom.Label, Amount: amount})
+			}
+		}
+		if len(unassigned) > 0 {
+			sites = append(sites, Site{Label: "unassigned", Capacity: 0, Cations: unassigned})
+		}
+		m.sites = &sites
+	}
+	return *m.sites, nil
+}
+
+func (m *MineralFormula) Output(printPrecision ...uint) (mfOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	apfu, err := m.APFU()
+	if err != nil {
+		return mfOutput{}, err
+	}
+	sum := 0.0
+	for _, atom := range apfu {
+		sum += atom.Amount
+	}
+
+	sites := []Site{}
+	if m.group != NoGroup {
+		sites, err = m.Sites()
+		if err != nil {
+			return mfOutput{}, err
+		}
+	}
+	pSites := make([]Site, len(sites))
+	for i, site := range sites {
+		pSites[i] = Site{Label: site.Label, Capacity: site.Capacity, Cations: roundAtomS(site.Cations, pPrecision)}
+	}
+
+	return mfOutput{
+		Group:   m.group.String(),
+		Oxides:  m.oxides,
+		Oxygens: m.oxygens,
+		APFU:    roundAtomS(apfu, pPrecision),
+		Sum:     utils.RoundFloat(sum, pPrecision),
+		Sites:   pSites,
+	}, nil
+}
+
+type mfOutput struct {
+	Group   string
+	Oxides  []Atom
+	Oxygens float64
+	APFU    []Atom
+	Sum     float64
+	Sites   []Site
+}
+
+func (o mfOutput) String() string {
+	out := fmt.Sprintln("group:", o.Group) +
+		fmt.Sprintln("oxides:", o.Oxides) +
+		fmt.Sprintln("oxygen basis:", o.Oxygens) +
+		fmt.Sprintln("cations:", o.APFU) +
+		fmt.Sprint("cation sum: ", o.Sum)
+	for _, site := range o.Sites {
+		out += fmt.Sprint("\n", site)
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package chemformula
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestMineralFormula_APFU(t *testing.T) {
+	tests := []struct {
+		name     string
+		oxides   []Atom
+		group    MineralGroup
+		expected []Atom
+	}{
+		{
+			name:   "diopside",
+			oxides: []Atom{{"SiO2", 55.49}, {"CaO", 25.9}, {"MgO", 18.61}},
+			group:  Pyroxene,
+			expected: []Atom{
+				{Label: "Si", Amount: 2},
+				{Label: "Ca", Amount: 1.0002},
+				{Label: "Mg", Amount: 0.9999}},
+		},
+		{
+			name:   "magnetite from FeO total",
+			oxides: []Atom{{"FeO", 93.0895}},
+			group:  Spinel,
+			expected: []Atom{
+				{Label: "Fe2+", Amount: 1},
+				{Label: "Fe3+", Amount: 2}},
+		},
+		{
+			name:   "almandine-rich garnet",
+			oxides: []Atom{{"SiO2", 36.5}, {"Al2O3", 20.5}, {"FeO", 34.0}, {"MgO", 4.0}, {"MnO", 1.5}, {"CaO", 3.0}},
+			group:  Garnet,
+			expected: []Atom{
+				{Label: "Si", Amount: 2.9334},
+				{Label: "Al", Amount: 1.9417},
+				{Label: "Mg", Amount: 0.4792},
+				{Label: "Mn", Amount: 0.1021},
+				{Label: "Ca", Amount: 0.2583},
+				{Label: "Fe2+", Amount: 2.0938},
+				{Label: "Fe3+", Amount: 0.1914}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMineralFormula(tt.oxides, tt.group, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := m.APFU()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("APFU() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMineralFormula_noFerricEstimate(t *testing.T) {
+	oxides := []Atom{{"SiO2", 68.74}, {"Al2O3", 19.44}, {"Na2O", 11.82}}
+	m, _ := NewMineralFormulaOnBasis(oxides, 8, 0, 4)
+	result, _ := m.APFU()
+	expected := []Atom{
+		{Label: "Si", Amount: 3},
+		{Label: "Al", Amount: 0.9999},
+		{Label: "Na", Amount: 1.0002}}
+	if !slices.Equal(result, expected) {
+		t.Errorf("APFU() = %v, expected %v", result, expected)
+	}
+	_, err := m.Sites()
+	if err == nil {
+		t.Errorf("Sites() should fail without mineral group")
+	}
+}
+
+func TestMineralFormula_Sites(t *testing.T) {
+	oxides := []Atom{{"SiO2", 36.5}, {"Al2O3", 20.5}, {"FeO", 34.0}, {"MgO", 4.0}, {"MnO", 1.5}, {"CaO", 3.0}}
+	m, _ := NewMineralFormula(oxides, Garnet)
+	out, _ := m.Output()
+	expected := `group: Garnet
+oxides: ['SiO2': 36.5 'Al2O3': 20.5 'FeO': 34 'MgO': 4 'MnO': 1.5 'CaO': 3]
+oxygen basis: 12
+cations: ['Si': 2.9334 'Al': 1.9417 'Mg': 0.4792 'Mn': 0.1021 'Ca': 0.2583 'Fe2+': 2.0938 'Fe3+': 0.1914]
+cation sum: 8
+Z (3): ['Si': 2.9334 'Al': 0.0666]
+Y (2): ['Al': 1.8751 'Fe3+': 0.1249]
+X (3): ['Mg': 0.4792 'Fe2+': 2.0938 'Mn': 0.1021 'Ca': 0.2583]
+unassigned (0): ['Fe3+': 0.0666]`
+	if out.String() != expected {
+		t.Errorf("Output() expected %s, got %s", expected, out)
+	}
+}
+
+func TestMineralFormula_SitesOverfilled(t *testing.T) {
+	m, _ := NewMineralFormula([]Atom{{"MgO", 100}}, Spinel)
+	sites, err := m.Sites()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[T (1): ['Mg': 1] M (2): ['Mg': 2] unassigned (0): ['Mg': 1]]"
+	if fmt.Sprint(sites) != expected {
+		t.Errorf("Sites() expected %s, got %v", expected, sites)
+	}
+}
+
+func TestMineralFormula_SitesAllowedCations(t *testing.T) {
+	m, _ := NewMineralFormula([]Atom{{"SiO2", 60}, {"MgO", 40}}, Pyroxene, 4)
+	sites, err := m.Sites()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[T (2): ['Si': 2] M1 (1): ['Mg': 1] M2 (1): ['Mg': 0.9918] unassigned (0): ['Si': 0.0041]]"
+	if fmt.Sprint(sites) != expected {
+		t.Errorf("Sites() expected %s, got %v", expected, sites)
+	}
+}
+
+func TestMineralFormula_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		oxides   []Atom
+		expected string
+	}{
+		{
+			name:     "not an oxide",
+			oxides:   []Atom{{"NaCl", 100}},
+			expected: "Only oxides can be considered as input (oxide 'NaCl')",
+		},
+		{
+			name:     "not binary",
+			oxides:   []Atom{{"CaCO3", 100}},
+			expected: "Only binary compounds can be considered as input (oxide 'CaCO3')",
+		},
+		{
+			name:     "invalid formula",
+			oxides:   []Atom{{"Xx2O", 100}},
+			expected: "There are invalid atom(s) [Xx] in the formula 'Xx2O'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := NewMineralFormula(tt.oxides, Pyroxene)
+			_, err := m.APFU()
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("APFU() error = %v, expected %s", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package chemformula
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+type ChemicalMixture struct {
+	components    []ChemicalFormula
+	fractions     []float64
+	basis         Basis
+	precision     uint
+	moleFractions *[]float64
+	parsedFormula *[]Atom
+	molarMass     *float64
+	massPercent   *[]Atom
+	atomicPercent *[]Atom
+	oxidePercent  *[]Atom
+}
+
+func NewChemicalMixture(components []Atom, basis Basis, precision ...uint) (*ChemicalMixture, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	if len(components) == 0 {
+		return nil, fmt.Errorf("Empty mixture")
+	}
+
+	formulas := make([]ChemicalFormula, len(components))
+	amounts := make([]float64, len(components))
+	for i, comp := range components {
+		if comp.Amount < 0 {
+			return nil, fmt.Errorf("Negative fraction %v of component '%s'", comp.Amount, comp.Label)
+		}
+		f, err := NewChemicalFormula(comp.Label, prec)
+		if err != nil {
+			return nil, err
+		}
+		formulas[i] = *f
+		amounts[i] = comp.Amount
+	}
+
+	sum := utils.SumFloatS(amounts)
+	if sum == 0 {
+		return nil, fmt.Errorf("Fractions of mixture components sum to zero")
+	}
+	for i := range amounts {
+		amounts[i] /= sum
+	}
+
+	return &ChemicalMixture{
+		components: formulas,
+		fractions:  amounts,
+		basis:      basis,
+		precision:  prec,
+	}, nil
+}
+
+func (m *ChemicalMixture) Components() []ChemicalFormula {
+	return m.components
+}
+
+func (m *ChemicalMixture) MoleFractions() []float64 {
+	if m.moleFractions == nil {
+		fractions := make([]float64, len(m.fractions))
+		copy(fractions, m.fractions)
+		if m.basis == MassBasis {
+			for i, comp := range m.components {
+				fractions[i] /= molarMass{comp.ParsedFormula()}.molarMass()
+			}
+			sum := utils.SumFloatS(fractions)
+			for i := range fractions {
+				fractions[i] /= sum
+			}
+		}
+		fractions = utils.RoundFloatS(fractions, m.precision)
+		m.moleFractions = &fractions
+	}
+	return *m.moleFractions
+}
+
+func (m *ChemicalMixture) MassFractions() []float64 {
+	fractions := make([]float64, len(m.fractions))
+	copy(fractions, m.fractions)
+	if m.basis == MoleBasis {
+		for i, comp := range m.components {
+			fractions[i] *= molarMass{comp.ParsedFormula()}.molarMass()
+		}
+		sum := utils.SumFloatS(fractions)
+		for i := range fractions {
+			fractions[i] /= sum
+		}
+	}
+	return utils.RoundFloatS(fractions, m.precision)
+}
+
+func (m *ChemicalMixture) ParsedFormula() []Atom {
+	if m.parsedFormula == nil {
+		moles := m.MoleFractions()
+		parsed := []Atom{}
+		index := make(map[string]int)
+		for i, comp := range m.components {
+			for _, atom := range comp.ParsedFormula() {
+				j, ok := index[atom.Label]
+				if !ok {
+					index[atom.Label] = len(parsed)
+					parsed = append(parsed, Atom{Label: atom.Label})
+					j = len(parsed) - 1
+				}
+				parsed[j].Amount += atom.Amount * moles[i]
+			}
+		}
+		parsed = roundAtomS(parsed, m.precision)
+		m.parsedFormula = &parsed
+	}
+	return *m.parsedFormula
+}
+
+func (m *ChemicalMixture) Formula() string {
+	var b strings.Builder
+	for _, atom := range m.ParsedFormula() {
+		if atom.Amount == 0 {
+			continue
+		}
+		b.WriteString(atom.Label)
+		if atom.Amount != 1 {
+			b.WriteString(strconv.FormatFloat(atom.Amount, 'f', -1, 64))
+		}
+	}
+	return b.String()
+}
+
+func (m *ChemicalMixture) ChemicalFormula() (*ChemicalFormula, error) {
+	return NewChemicalFormula(m.Formula(), m.precision)
+}
+
+func (m *ChemicalMixture) MolarMass() float64 {
+	if m.molarMass == nil {
+		mass := molarMass{m.ParsedFormula()}.molarMass()
+		mass = utils.RoundFloat(mass, m.precision)
+		m.molarMass = &mass
+	}
+	return *m.molarMass
+}
+
+func (m *ChemicalMixture) MassPercent() []Atom {
+	if m.massPercent == nil {
+		percent := molarMass{m.ParsedFormula()}.massPercent()
+		percent = roundAtomS(percent, m.precision)
+		m.massPercent = &percent
+	}
+	return *m.massPercent
+}
+
+func (m *ChemicalMixture) AtomicPercent() []Atom {
+	if m.atomicPercent == nil {
+		percent := molarMass{m.ParsedFormula()}.atomicPercent()
+		percent = roundAtomS(percent, m.precision)
+		m.atomicPercent = &percent
+	}
+	return *m.atomicPercent
+}
+
+func (m *ChemicalMixture) OxidePercent(inOxides ...string) ([]Atom, error) {
+	if m.oxidePercent == nil {
+		percent, err := molarMass{m.ParsedFormula()}.oxidePercent(inOxides...)
+		if err != nil {
+			return nil, err
+		}
+		percent = roundAtomS(percent, m.precision)
+		m.oxidePercent = &percent
+	}
+	return *m.oxidePercent, nil
+}
+
+func (m *ChemicalMixture) Output(printPrecision ...uint) cmOutput {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	components := make([]Atom, len(m.components))
+	for i, comp := range m.components {
+		components[i] = Atom{Label: comp.Formula(), Amount: m.fractions[i] * 100}
+	}
+
+	oxides, _ := m.OxidePercent()
+	return cmOutput{
+		Components:    roundAtomS(components, pPrecision),
+		Basis:         m.basis.String(),
+		Formula:       m.Formula(),
+		MolarMass:     utils.RoundFloat(m.MolarMass(), pPrecision),
+		MassPercent:   roundAtomS(m.MassPercent(), pPrecision),
+		AtomicPercent: roundAtomS(m.AtomicPercent(), pPrecision),
+		OxidePercent:  roundAtomS(oxides, pPrecision),
+	}
+}
+
+type cmOutput struct {
+	Components    []Atom
+	Basis         string
+	Formula       string
+	MolarMass     float64
+	MassPercent   []Atom
+	AtomicPercent []Atom
+	OxidePercent  []Atom
+}
+
+func (o cmOutput) String() string {
+	comps := fmt.Sprintf("components (%s): %v\n", o.Basis, o.Components)
+	form := fmt.Sprintln("formula:", o.Formula)
+	mMass := fmt.Sprintln("molar mass:", o.MolarMass)
+	mPercent := fmt.Sprintln("mass percent:", o.MassPercent)
+	aPercent := fmt.Sprintln("atomic percent:", o.AtomicPercent)
+	oPercent := fmt.Sprint("oxide percent: ", o.OxidePercent)
+	return comps + form + mMass + mPercent + aPercent + oPercent
+}
@@ -0,0 +1,89 @@
+package chemformula
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChemicalMixtureOutput(t *testing.T) {
+	m, err := NewChemicalMixture([]Atom{{"CaCO3", 95}, {"MgCO3", 3}, {"SiO2", 2}}, MassBasis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := m.Output().String()
+	expected := `components (wt%): ['CaCO3': 95 'MgCO3': 3 'SiO2': 2]
+formula: Ca0.93235224C0.96730299O2.96730301Mg0.03495075Si0.03269702
+molar mass: 98.2267
+mass percent: ['Ca': 38.0414 'C': 11.828 'O': 48.3309 'Mg': 0.8648 'Si': 0.9349]
+atomic percent: ['Ca': 18.8942 'C': 19.6024 'O': 60.1325 'Mg': 0.7083 'Si': 0.6626]
+oxide percent: ['CaO': 53.2274 'CO2': 43.3385 'MgO': 1.4341 'SiO2': 2]`
+	if got != expected {
+		t.Errorf("Output() expected %s, got %s", expected, got)
+	}
+}
+
+func TestChemicalMixture_fractions(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Atom
+		basis      Basis
+		moles      []float64
+		masses     []float64
+		molarMass  float64
+	}{
+		{
+			name:       "mole basis",
+			components: []Atom{{"H2O", 1}, {"C2H5OH", 1}},
+			basis:      MoleBasis,
+			moles:      []float64{0.5, 0.5},
+			masses:     []float64{0.28111541, 0.71888459},
+			molarMass:  32.042,
+		},
+		{
+			name:       "mass basis",
+			components: []Atom{{"H2O", 18.015}, {"CO2", 44.009}},
+			basis:      MassBasis,
+			moles:      []float64{0.5, 0.5},
+			masses:     []float64{0.29045208, 0.70954792},
+			molarMass:  31.012,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewChemicalMixture(tt.components, tt.basis)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := m.MoleFractions(); !slices.Equal(got, tt.moles) {
+				t.Errorf("MoleFractions() = %v, expected %v", got, tt.moles)
+			}
+			if got := m.MassFractions(); !slices.Equal(got, tt.masses) {
+				t.Errorf("MassFractions() = %v, expected %v", got, tt.masses)
+			}
+			if got := m.MolarMass(); got != tt.molarMass {
+				t.Errorf("MolarMass() = %v, expected %v", got, tt.molarMass)
+			}
+		})
+	}
+}
+
+func TestChemicalMixture_errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Atom
+		expected   string
+	}{
+		{"empty", []Atom{}, "Empty mixture"},
+		{"negative", []Atom{{"H2O", -1}}, "Negative fraction -1 of component 'H2O'"},
+		{"zero sum", []Atom{{"H2O", 0}}, "Fractions of mixture components sum to zero"},
+		{"invalid formula", []Atom{{"Xx", 1}}, "There are invalid atom(s) [Xx] in the formula 'Xx'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewChemicalMixture(tt.components, MassBasis)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("NewChemicalMixture() error = %v, expected %s", err, tt.expected)
+			}
+		})
+	}
+}
@@ -52,23 +52,31 @@ func (m molarMass) atomicPercent() []Atom {
 	return percent
 }
 
+func parseOxide(formula string) ([]Atom, error) {
+	validator := formulaValidator{formula: formula}
+	err := validator.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := chemicalFormulaParser{}.parse(formula)
+	if len(parsed) != 2 {
+		return nil, fmt.Errorf("Only binary compounds can be considered as input (oxide '%s')", formula)
+	} else if parsed[1].Label != "O" {
+		return nil, fmt.Errorf("Only oxides can be considered as input (oxide '%s')", formula)
+	}
+	return parsed, nil
+}
+
 func (m molarMass) customOxides(inOxides ...string) ([]oxide, error) {
 	oxides := []oxide{}
 	metals := []string{}
 	for _, cOxide := range inOxides {
-		validator := formulaValidator{formula: cOxide}
-		err := validator.validate()
+		parsed, err := parseOxide(cOxide)
 		if err != nil {
 			return nil, err
 		}
 
-		parsed := chemicalFormulaParser{}.parse(cOxide)
-		if len(parsed) > 2 {
-			return nil, fmt.Errorf("Only binary compounds can be considered as input (oxide '%s')", cOxide)
-		} else if parsed[1].Label != "O" {
-			return nil, fmt.Errorf("Only oxides can be considered as input (oxide '%s')", cOxide)
-		}
-
 		metals = append(metals, parsed[0].Label)
 	}
 
@@ -33,6 +33,10 @@ var formRegexes regexes = regexes{
 	adductSymbols:    []rune{'*', '·', '•'},
 }
 
+func AdductSymbols() []rune {
+	return slices.Clone(formRegexes.adductSymbols)
+}
+
 type Atom struct {
 	Label  string
 	Amount float64
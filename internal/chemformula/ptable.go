@@ -1,5 +1,7 @@
 package chemformula
 
+import "slices"
+
 type element struct {
 	weight       float64
 	defaultOxide string
@@ -137,3 +139,13 @@ func getKeys(p pTable) []string {
 	}
 	return keys
 }
+
+var nonMetals = []string{
+	"H", "He", "B", "C", "N", "O", "F", "Ne", "Si", "P", "S", "Cl", "Ar",
+	"As", "Se", "Br", "Kr", "Te", "I", "Xe", "At", "Rn",
+}
+
+func IsMetal(element string) bool {
+	_, ok := periodicTable[element]
+	return ok && !slices.Contains(nonMetals, element)
+}
@@ -0,0 +1,23 @@
+package chemformula
+
+import "testing"
+
+func TestIsMetal(t *testing.T) {
+	tests := []struct {
+		element  string
+		expected bool
+	}{
+		{"Fe", true},
+		{"Li", true},
+		{"La", true},
+		{"O", false},
+		{"Si", false},
+		{"C", false},
+		{"Xx", false},
+	}
+	for _, tt := range tests {
+		if got := IsMetal(tt.element); got != tt.expected {
+			t.Errorf("IsMetal(%s) = %v, expected %v", tt.element, got, tt.expected)
+		}
+	}
+}
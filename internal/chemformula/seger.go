@@ -0,0 +1,311 @@
+package chemformula
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+type SegerGroup int
+
+const (
+	Flux SegerGroup = iota
+	Amphoteric
+	GlassFormer
+)
+
+func (g SegerGroup) String() string {
+	return [...]string{"RO/R2O", "R2O3", "RO2"}[g]
+}
+
+var glazeOxides = map[string]struct {
+	oxide string
+	group SegerGroup
+}{
+	"Li": {"Li2O", Flux},
+	"Na": {"Na2O", Flux},
+	"K":  {"K2O", Flux},
+	"Mg": {"MgO", Flux},
+	"Ca": {"CaO", Flux},
+	"Sr": {"SrO", Flux},
+	"Ba": {"BaO", Flux},
+	"Zn": {"ZnO", Flux},
+	"Pb": {"PbO", Flux},
+	"Mn": {"MnO", Flux},
+	"Co": {"CoO", Flux},
+	"Ni": {"NiO", Flux},
+	"Cu": {"CuO", Flux},
+	"Al": {"Al2O3", Amphoteric},
+	"B":  {"B2O3", Amphoteric},
+	"Fe": {"Fe2O3", Amphoteric},
+	"Cr": {"Cr2O3", Amphoteric},
+	"Sb": {"Sb2O3", Amphoteric},
+	"Si": {"SiO2", GlassFormer},
+	"Ti": {"TiO2", GlassFormer},
+	"Zr": {"ZrO2", GlassFormer},
+	"Sn": {"SnO2", GlassFormer},
+	"P":  {"P2O5", GlassFormer},
+}
+
+var nonOxideAnions = []string{"F", "Cl", "Br", "I", "S"}
+
+func segerGroup(oxide string, parsedOxide []Atom) SegerGroup {
+	if glaze, ok := glazeOxides[parsedOxide[0].Label]; ok && glaze.oxide == oxide {
+		return glaze.group
+	}
+	ratio := parsedOxide[1].Amount / parsedOxide[0].Amount
+	switch {
+	case ratio <= 1:
+		return Flux
+	case ratio <= 1.5:
+		return Amphoteric
+	default:
+		return GlassFormer
+	}
+}
+
+type SegerFormula struct {
+	oxides    []Atom
+	groups    []SegerGroup
+	precision uint
+}
+
+func NewSegerFormula(oxides []Atom, precision ...uint) (*SegerFormula, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	if len(oxides) == 0 {
+		return nil, fmt.Errorf("Empty oxide composition")
+	}
+
+	labels := make([]string, len(oxides))
+	for i, ox := range oxides {
+		if ox.Amount < 0 {
+			return nil, fmt.Errorf("Negative amount %v of oxide '%s'", ox.Amount, ox.Label)
+		}
+		labels[i] = ox.Label
+	}
+	oMap, err := newOxideMap(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	fluxSum := 0.0
+	groups := make([]SegerGroup, len(oxides))
+	for i, parsed := range oMap.parsed {
+		groups[i] = segerGroup(labels[i], parsed)
+		if groups[i] == Flux {
+			fluxSum += oxides[i].Amount
+		}
+	}
+	if fluxSum == 0 {
+		return nil, fmt.Errorf("There are no fluxes (RO/R2O) in the composition %v", oxides)
+	}
+
+	normalized := []Atom{}
+	sorted := []SegerGroup{}
+	for _, group := range []SegerGroup{Flux, Amphoteric, GlassFormer} {
+		for i, ox := range oxides {
+			if groups[i] == group {
+				normalized = append(normalized, Atom{Label: ox.Label, Amount: ox.Amount / fluxSum})
+				sorted = append(sorted, group)
+			}
+		}
+	}
+
+	return &SegerFormula{
+		oxides:    roundAtomS(normalized, prec),
+		groups:    sorted,
+		precision: prec,
+	}, nil
+}
+
+func NewSegerFormulaFromRecipe(recipe []Atom, inOxides []string, precision ...uint) (*SegerFormula, error) {
+	if len(recipe) == 0 {
+		return nil, fmt.Errorf("Empty recipe")
+	}
+
+	custom := make(map[string]string)
+	for _, cOxide := range inOxides {
+		parsed, err := parseOxide(cOxide)
+		if err != nil {
+			return nil, err
+		}
+		custom[parsed[0].Label] = cOxide
+	}
+
+	labels := []string{}
+	parsedMaterials := make([][]Atom, len(recipe))
+	for i, material := range recipe {
+		if material.Amount < 0 {
+			return nil, fmt.Errorf("Negative amount %v of raw material '%s'", material.Amount, material.Label)
+		}
+		formula := strings.Replace(material.Label, " ", "", -1)
+		validator := formulaValidator{formula: formula}
+		err := validator.validate()
+		if err != nil {
+			return nil, err
+		}
+		parsedMaterials[i] = chemicalFormulaParser{}.parse(formula)
+		for _, atom := range parsedMaterials[i] {
+			if atom.Label == "O" || slices.Contains(volatileElements, atom.Label) {
+				continue
+			}
+			if slices.Contains(nonOxideAnions, atom.Label) {
+				return nil, fmt.Errorf("Non-oxide anion %s of raw material '%s' can't be expressed in the Seger formula", atom.Label, material.Label)
+			}
+			label, ok := custom[atom.Label]
+			if !ok {
+				label = periodicTable[atom.Label].defaultOxide
+				if glaze, ok := glazeOxides[atom.Label]; ok {
+					label = glaze.oxide
+				}
+			}
+			if !slices.Contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+
+	oMap, err := newOxideMap(labels)
+	if err != nil {
+		return nil, err
+	}
+	amounts := make([]float64, len(labels))
+	for i, material := range recipe {
+		yields, err := oMap.moleYields(strings.Replace(material.Label, " ", "", -1))
+		if err != nil {
+			return nil, err
+		}
+		moles := material.Amount / molarMass{parsedMaterials[i]}.molarMass()
+		for j, y := range yields {
+			amounts[j] += y * moles
+		}
+	}
+
+	oxides := make([]Atom, len(labels))
+	for i, label := range labels {
+		oxides[i] = Atom{Label: label, Amount: amounts[i]}
+	}
+	return NewSegerFormula(oxides, precision...)
+}
+
+func (s *SegerFormula) Oxides() []Atom {
+	return s.oxides
+}
+
+func (s *SegerFormula) group(g SegerGroup) []Atom {
+	ret := []Atom{}
+	for i, ox := range s.oxides {
+		if s.groups[i] == g {
+			ret = append(ret, ox)
+		}
+	}
+	return ret
+}
+
+func (s *SegerFormula) Fluxes() []Atom {
+	return s.group(Flux)
+}
+
+func (s *SegerFormula) Amphoterics() []Atom {
+	return s.group(Amphoteric)
+}
+
+func (s *SegerFormula) GlassFormers() []Atom {
+	return s.group(GlassFormer)
+}
+
+func (s *SegerFormula) SiAlRatio() float64 {
+	var si, al float64
+	for _, ox := range s.oxides {
+		switch ox.Label {
+		case "SiO2":
+			si = ox.Amount
+		case "Al2O3":
+			al = ox.Amount
+		}
+	}
+	if al == 0 {
+		return 0
+	}
+	return utils.RoundFloat(si/al, s.precision)
+}
+
+func (s *SegerFormula) Recipe(materials []string, batchMass float64) ([]float64, error) {
+	if batchMass <= 0 {
+		return nil, fmt.Errorf("Batch mass %v should be > 0", batchMass)
+	}
+
+	labels := make([]string, len(s.oxides))
+	for i, ox := range s.oxides {
+		labels[i] = ox.Label
+	}
+	oMap, err := newOxideMap(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	newMaterials := make([]string, len(materials))
+	for i, material := range materials {
+		newMaterials[i] = strings.Replace(material, " ", "", -1)
+	}
+	yields, err := oMap.massYields(newMaterials)
+	if err != nil {
+		return nil, err
+	}
+
+	oxMasses := oMap.molarMasses()
+	target := make([]float64, len(s.oxides))
+	for i, ox := range s.oxides {
+		target[i] = ox.Amount * oxMasses[i]
+	}
+	masses, _, err := utils.NNLS(yields, target, 1e-12)
+	if err != nil {
+		return nil, err
+	}
+
+	total := utils.SumFloatS(masses)
+	if total == 0 {
+		return nil, fmt.Errorf("The formula can't be obtained from the raw materials %v", materials)
+	}
+	scale := batchMass / total
+	for i := range masses {
+		masses[i] *= scale
+	}
+	return utils.RoundFloatS(masses, s.precision), nil
+}
+
+func (s *SegerFormula) Output(printPrecision ...uint) segerOutput {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	return segerOutput{
+		Fluxes:       roundAtomS(s.Fluxes(), pPrecision),
+		Amphoterics:  roundAtomS(s.Amphoterics(), pPrecision),
+		GlassFormers: roundAtomS(s.GlassFormers(), pPrecision),
+		SiAlRatio:    utils.RoundFloat(s.SiAlRatio(), pPrecision),
+	}
+}
+
+type segerOutput struct {
+	Fluxes       []Atom
+	Amphoterics  []Atom
+	GlassFormers []Atom
+	SiAlRatio    float64
+}
+
+func (o segerOutput) String() string {
+	return fmt.Sprintln("RO/R2O:", o.Fluxes) +
+		fmt.Sprintln("R2O3:", o.Amphoterics) +
+		fmt.Sprintln("RO2:", o.GlassFormers) +
+		fmt.Sprint("SiO2:Al2O3: ", o.SiAlRatio)
+}
@@ -0,0 +1,112 @@
+package chemformula
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+func TestSegerFormulaFromRecipe(t *testing.T) {
+	recipe := []Atom{{"KAlSi3O8", 40}, {"CaCO3", 20}, {"Al2Si2O5(OH)4", 10}, {"SiO2", 30}}
+	s, err := NewSegerFormulaFromRecipe(recipe, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `RO/R2O: ['K2O': 0.2645 'CaO': 0.7355]
+R2O3: ['Al2O3': 0.4071]
+RO2: ['SiO2': 3.7099]
+SiO2:Al2O3: 9.1138`
+	if got := s.Output().String(); got != expected {
+		t.Errorf("Output() expected %s, got %s", expected, got)
+	}
+}
+
+func TestSegerFormula_Recipe(t *testing.T) {
+	recipe := []Atom{{"KAlSi3O8", 40}, {"CaCO3", 20}, {"Al2Si2O5(OH)4", 10}, {"SiO2", 30}}
+	s, _ := NewSegerFormulaFromRecipe(recipe, []string{"K2O"})
+	materials := []string{"KAlSi3O8", "CaCO3", "Al2Si2O5(OH)4", "SiO2"}
+	got, err := s.Recipe(materials, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{40, 20, 10, 30}
+	for i := range got {
+		if math.Abs(got[i]-expected[i]) > 1e-5 {
+			t.Errorf("Recipe() = %v, expected %v", got, expected)
+			break
+		}
+	}
+}
+
+func TestSegerFormula_groups(t *testing.T) {
+	s, _ := NewSegerFormula([]Atom{{"SiO2", 3}, {"B2O3", 0.5}, {"Na2O", 0.5}, {"CaO", 1.5}})
+	tests := []struct {
+		name     string
+		got      []Atom
+		expected []Atom
+	}{
+		{"fluxes", s.Fluxes(), []Atom{{"Na2O", 0.25}, {"CaO", 0.75}}},
+		{"amphoterics", s.Amphoterics(), []Atom{{"B2O3", 0.25}}},
+		{"glass formers", s.GlassFormers(), []Atom{{"SiO2", 1.5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.got, tt.expected) {
+				t.Errorf("got %v, expected %v", tt.got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChemicalFormula_SegerFormula(t *testing.T) {
+	f, _ := NewChemicalFormula("NaAlSi3O8")
+	s, err := f.SegerFormula()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Atom{{"Na2O", 1}, {"Al2O3", 1}, {"SiO2", 6}}
+	if !slices.Equal(s.Oxides(), expected) {
+		t.Errorf("SegerFormula() = %v, expected %v", s.Oxides(), expected)
+	}
+}
+
+func TestSegerFormula_noFluxes(t *testing.T) {
+	_, err := NewSegerFormula([]Atom{{"SiO2", 3}, {"Al2O3", 1}})
+	expected := "There are no fluxes (RO/R2O) in the composition ['SiO2': 3 'Al2O3': 1]"
+	if err == nil || err.Error() != expected {
+		t.Errorf("NewSegerFormula() error = %v, expected %s", err, expected)
+	}
+}
+
+func TestSegerFormulaFromRecipe_glazeOxides(t *testing.T) {
+	recipe := []Atom{{"CaCO3", 20}, {"MnCO3", 2}, {"CoCO3", 1}, {"Ca3(PO4)2", 3}, {"SiO2", 30}}
+	s, err := NewSegerFormulaFromRecipe(recipe, nil, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"CaO", "MnO", "CoO", "P2O5", "SiO2"}
+	labels := []string{}
+	for _, group := range [][]Atom{s.Fluxes(), s.Amphoterics(), s.GlassFormers()} {
+		for _, ox := range group {
+			labels = append(labels, ox.Label)
+		}
+	}
+	if !slices.Equal(labels, expected) || len(s.GlassFormers()) != 2 {
+		t.Errorf("oxides = %v, expected %v with P2O5 and SiO2 as glass formers", s.Oxides(), expected)
+	}
+	for _, ox := range s.Oxides() {
+		if ox.Amount != utils.RoundFloat(ox.Amount, 4) {
+			t.Errorf("oxide %v is not rounded to precision 4", ox)
+		}
+	}
+}
+
+func TestSegerFormulaFromRecipe_nonOxideAnion(t *testing.T) {
+	_, err := NewSegerFormulaFromRecipe([]Atom{{"CaF2", 10}, {"SiO2", 30}}, nil)
+	expected := "Non-oxide anion F of raw material 'CaF2' can't be expressed in the Seger formula"
+	if err == nil || err.Error() != expected {
+		t.Errorf("NewSegerFormulaFromRecipe() error = %v, expected %s", err, expected)
+	}
+}
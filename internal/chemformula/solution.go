@@ -0,0 +1,252 @@
+package chemformula
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+type ConcentrationUnit int
+
+const (
+	Molarity ConcentrationUnit = iota
+	Molality
+	MassFraction
+	MoleFraction
+	PPM
+	GramsPerLiter
+)
+
+var concentrationUnits = []ConcentrationUnit{Molarity, Molality, MassFraction, MoleFraction, PPM, GramsPerLiter}
+
+func (u ConcentrationUnit) String() string {
+	return [...]string{"mol/L", "mol/kg", "mass fraction", "mole fraction", "ppm", "g/L"}[u]
+}
+
+func (u ConcentrationUnit) volumetric() bool {
+	return u == Molarity || u == GramsPerLiter
+}
+
+func anhydrousFormula(formula string) string {
+	if i := strings.IndexAny(formula, string(formRegexes.adductSymbols)); i > 0 {
+		return formula[:i]
+	}
+	return formula
+}
+
+type Solution struct {
+	weighed       *ChemicalFormula
+	solute        *ChemicalFormula
+	solvent       *ChemicalFormula
+	concentration float64
+	unit          ConcentrationUnit
+	density       float64
+	precision     uint
+}
+
+func NewSolution(solute string, solvent string, concentration float64, unit ConcentrationUnit, density float64, precision ...uint) (*Solution, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	weighed, err := NewChemicalFormula(solute, prec)
+	if err != nil {
+		return nil, err
+	}
+	anhydrous, err := NewChemicalFormula(anhydrousFormula(weighed.Formula()), prec)
+	if err != nil {
+		return nil, err
+	}
+	solv, err := NewChemicalFormula(solvent, prec)
+	if err != nil {
+		return nil, err
+	}
+	if concentration < 0 {
+		return nil, fmt.Errorf("Negative concentration %v", concentration)
+	}
+	if (unit == MassFraction || unit == MoleFraction) && concentration > 1 {
+		return nil, fmt.Errorf("%s %v should be <= 1", unit, concentration)
+	}
+	if density < 0 {
+		return nil, fmt.Errorf("Negative density %v", density)
+	}
+
+	return &Solution{
+		weighed:       weighed,
+		solute:        anhydrous,
+		solvent:       solv,
+		concentration: concentration,
+		unit:          unit,
+		density:       density,
+		precision:     prec,
+	}, nil
+}
+
+func (s *Solution) Solute() string {
+	return s.solute.Formula()
+}
+
+func (s *Solution) WeighedForm() string {
+	return s.weighed.Formula()
+}
+
+func (s *Solution) soluteMolar() float64 {
+	return molarMass{s.solute.ParsedFormula()}.molarMass()
+}
+
+func (s *Solution) solventMolar() float64 {
+	return molarMass{s.solvent.ParsedFormula()}.molarMass()
+}
+
+func (s *Solution) massFraction() (float64, error) {
+	m := s.soluteMolar()
+	c := s.concentration
+	switch s.unit {
+	case Molality:
+		return c * m / (1000 + c*m), nil
+	case MassFraction:
+		return c, nil
+	case MoleFraction:
+		return c * m / (c*m + (1-c)*s.solventMolar()), nil
+	case PPM:
+		return c * 1e-6, nil
+	}
+	if s.density == 0 {
+		return 0, fmt.Errorf("Density of the solution is required to convert %s", s.unit)
+	}
+	if s.unit == Molarity {
+		return c * m / (1000 * s.density), nil
+	}
+	return c / (1000 * s.density), nil
+}
+
+func (s *Solution) Concentration(unit ConcentrationUnit) (float64, error) {
+	m := s.soluteMolar()
+	switch {
+	case unit == s.unit:
+		return utils.RoundFloat(s.concentration, s.precision), nil
+	case unit == Molarity && s.unit == GramsPerLiter:
+		return utils.RoundFloat(s.concentration/m, s.precision), nil
+	case unit == GramsPerLiter && s.unit == Molarity:
+		return utils.RoundFloat(s.concentration*m, s.precision), nil
+	}
+
+	w, err := s.massFraction()
+	if err != nil {
+		return 0, err
+	}
+	var res float64
+	switch unit {
+	case Molality:
+		if w == 1 {
+			return 0, fmt.Errorf("Molality of a pure solute is undefined")
+		}
+		res = 1000 * w / (m * (1 - w))
+	case MassFraction:
+		res = w
+	case MoleFraction:
+		res = (w / m) / (w/m + (1-w)/s.solventMolar())
+	case PPM:
+		res = w * 1e6
+	default:
+		if s.density == 0 {
+			return 0, fmt.Errorf("Density of the solution is required to convert to %s", unit)
+		}
+		res = 1000 * s.density * w
+		if unit == Molarity {
+			res /= m
+		}
+	}
+	return utils.RoundFloat(res, s.precision), nil
+}
+
+func (s *Solution) Conversions() []Atom {
+	conversions := []Atom{}
+	for _, unit := range concentrationUnits {
+		c, err := s.Concentration(unit)
+		if err == nil {
+			conversions = append(conversions, Atom{Label: unit.String(), Amount: c})
+		}
+	}
+	return conversions
+}
+
+func (s *Solution) WeighedMass(volume float64) (float64, error) {
+	if volume <= 0 {
+		return 0, fmt.Errorf("Volume %v should be > 0", volume)
+	}
+	c, err := s.Concentration(Molarity)
+	if err != nil {
+		return 0, err
+	}
+	mass := c * volume / 1000 * molarMass{s.weighed.ParsedFormula()}.molarMass()
+	return utils.RoundFloat(mass, s.precision), nil
+}
+
+func (s *Solution) Instructions(volume float64, printPrecision ...uint) (string, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	mass, err := s.WeighedMass(volume)
+	if err != nil {
+		return "", err
+	}
+	out := fmt.Sprintf("weigh %v g of %s, dissolve in %s and fill the %v mL volumetric flask to the mark",
+		utils.RoundFloat(mass, pPrecision), s.weighed.Formula(), s.solvent.Formula(), volume)
+	if s.density > 0 {
+		out += fmt.Sprintf(" (solution mass %v g)", utils.RoundFloat(s.density*volume, pPrecision))
+	}
+	return out, nil
+}
+
+func (s *Solution) Output(printPrecision ...uint) solOutput {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	return solOutput{
+		Solute:      s.solute.Formula(),
+		WeighedForm: s.weighed.Formula(),
+		Solvent:     s.solvent.Formula(),
+		Density:     s.density,
+		Conversions: roundAtomS(s.Conversions(), pPrecision),
+	}
+}
+
+type solOutput struct {
+	Solute      string
+	WeighedForm string
+	Solvent     string
+	Density     float64
+	Conversions []Atom
+}
+
+func (o solOutput) String() string {
+	out := fmt.Sprintln("solute:", o.Solute) +
+		fmt.Sprintln("weighed form:", o.WeighedForm) +
+		fmt.Sprintln("solvent:", o.Solvent)
+	if o.Density > 0 {
+		out += fmt.Sprintf("density: %v g/mL\n", o.Density)
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	for _, c := range o.Conversions {
+		fmt.Fprintf(w, "%s\t%v\n", c.Label, c.Amount)
+	}
+
+	w.Flush()
+	return out + strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,83 @@
+package chemformula
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSolution_Concentration(t *testing.T) {
+	s, err := NewSolution("NaCl", "H2O", 1, Molarity, 1.0369)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		unit     ConcentrationUnit
+		expected float64
+	}{
+		{Molarity, 1},
+		{GramsPerLiter, 58.443},
+		{MassFraction, 0.05636},
+		{Molality, 1.0221},
+		{MoleFraction, 0.01808},
+		{PPM, 56363},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit.String(), func(t *testing.T) {
+			got, err := s.Concentration(tt.unit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.expected)/tt.expected > 1e-3 {
+				t.Errorf("Concentration(%s) = %v, expected %v", tt.unit, got, tt.expected)
+			}
+			back, err := NewSolution("NaCl", "H2O", got, tt.unit, 1.0369)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, _ := back.Concentration(Molarity)
+			if math.Abs(m-1) > 1e-6 {
+				t.Errorf("round trip from %s gives %v mol/L", tt.unit, m)
+			}
+		})
+	}
+}
+
+func TestSolution_noDensity(t *testing.T) {
+	s, _ := NewSolution("NaCl", "H2O", 0.5, Molarity, 0)
+	if got, err := s.Concentration(GramsPerLiter); err != nil || math.Abs(got-29.2199) > 1e-3 {
+		t.Errorf("Concentration(g/L) = %v, %v", got, err)
+	}
+	if _, err := s.Concentration(Molality); err == nil {
+		t.Error("expected error for missing density")
+	}
+	if got := len(s.Conversions()); got != 2 {
+		t.Errorf("len(Conversions()) = %d, expected 2", got)
+	}
+}
+
+func TestSolution_Instructions(t *testing.T) {
+	s, err := NewSolution("CuSO4*5H2O", "H2O", 0.1, Molarity, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Solute() != "CuSO4" {
+		t.Errorf("Solute() = %s, expected CuSO4", s.Solute())
+	}
+	got, err := s.Instructions(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "weigh 2.4968 g of CuSO4*5H2O, dissolve in H2O and fill the 100 mL volumetric flask to the mark"
+	if got != expected {
+		t.Errorf("Instructions() = %s, expected %s", got, expected)
+	}
+}
+
+func TestNewSolution_invalid(t *testing.T) {
+	if _, err := NewSolution("NaCl", "H2O", 1.2, MassFraction, 0); err == nil {
+		t.Error("expected error for mass fraction > 1")
+	}
+	if _, err := NewSolution("NaCl", "H2O", -1, Molarity, 0); err == nil {
+		t.Error("expected error for negative concentration")
+	}
+}
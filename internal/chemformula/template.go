@@ -0,0 +1,291 @@
+package chemformula
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+type tokenKind int
+
+const (
+	literalToken tokenKind = iota
+	leadToken
+	elementToken
+	openerToken
+	closerToken
+	adductToken
+)
+
+type exprTerm struct {
+	sign     float64
+	coef     float64
+	variable string
+}
+
+type templateToken struct {
+	kind tokenKind
+	text string
+	expr []exprTerm
+}
+
+type templateScanner struct {
+	runes []rune
+	i     int
+}
+
+func (s *templateScanner) peek(offset int) rune {
+	if s.i+offset < len(s.runes) {
+		return s.runes[s.i+offset]
+	}
+	return 0
+}
+
+func isVariable(r rune) bool {
+	return unicode.IsLower(r)
+}
+
+func (s *templateScanner) term() (exprTerm, bool, error) {
+	start := s.i
+	for s.i < len(s.runes) && (unicode.IsDigit(s.runes[s.i]) || s.runes[s.i] == '.') {
+		s.i++
+	}
+	term := exprTerm{sign: 1, coef: 1}
+	if s.i > start {
+		coef, err := strconv.ParseFloat(string(s.runes[start:s.i]), 64)
+		if err != nil {
+			return term, false, fmt.Errorf("Invalid number '%s' in the template", string(s.runes[start:s.i]))
+		}
+		term.coef = coef
+	}
+	if isVariable(s.peek(0)) {
+		term.variable = string(s.peek(0))
+		s.i++
+	}
+	return term, s.i > start, nil
+}
+
+func (s *templateScanner) expression() ([]exprTerm, error) {
+	term, ok, err := s.term()
+	if err != nil || !ok {
+		return nil, err
+	}
+	expr := []exprTerm{term}
+	for s.peek(0) == '-' && (unicode.IsDigit(s.peek(1)) || isVariable(s.peek(1))) {
+		s.i++
+		term, _, err := s.term()
+		if err != nil {
+			return nil, err
+		}
+		term.sign = -1
+		expr = append(expr, term)
+	}
+	if unicode.IsDigit(s.peek(0)) || s.peek(0) == '.' || isVariable(s.peek(0)) {
+		return nil, fmt.Errorf("Invalid subscript expression at position %d of the template", s.i)
+	}
+	return expr, nil
+}
+
+func (s *templateScanner) element() (string, error) {
+	end := s.i + 1
+	for end < len(s.runes) && end-s.i < 3 && unicode.IsLower(s.runes[end]) {
+		end++
+	}
+	for ; end > s.i; end-- {
+		symbol := string(s.runes[s.i:end])
+		if slices.Contains(periodicTableElements, symbol) {
+			s.i = end
+			return symbol, nil
+		}
+	}
+	return "", fmt.Errorf("There is invalid atom at position %d of the template", s.i)
+}
+
+func scanTemplate(template string) ([]templateToken, error) {
+	s := &templateScanner{runes: []rune(strings.Replace(template, " ", "", -1))}
+	tokens := []templateToken{}
+	lead := true
+	for s.i < len(s.runes) {
+		r := s.runes[s.i]
+		var token templateToken
+		switch {
+		case lead && (unicode.IsDigit(r) || isVariable(r)):
+			token.kind = leadToken
+		case unicode.IsUpper(r):
+			symbol, err := s.element()
+			if err != nil {
+				return nil, err
+			}
+			token = templateToken{kind: elementToken, text: symbol}
+		case slices.Contains(formRegexes.openerBrackets, r):
+			token = templateToken{kind: openerToken, text: string(r)}
+			s.i++
+		case slices.Contains(formRegexes.closerBrackets, r):
+			token = templateToken{kind: closerToken, text: string(r)}
+			s.i++
+		case slices.Contains(formRegexes.adductSymbols, r):
+			token = templateToken{kind: adductToken, text: string(r)}
+			s.i++
+		case unicode.IsDigit(r) || isVariable(r):
+			return nil, fmt.Errorf("Unexpected symbol '%c' at position %d of the template", r, s.i)
+		default:
+			token = templateToken{kind: literalToken, text: string(r)}
+			s.i++
+		}
+
+		if token.kind != literalToken && token.kind != openerToken {
+			expr, err := s.expression()
+			if err != nil {
+				return nil, err
+			}
+			token.expr = expr
+		}
+		lead = token.kind == literalToken
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func evalExpression(expr []exprTerm, values map[string]float64) (float64, error) {
+	res := 0.0
+	for _, term := range expr {
+		val := 1.0
+		if term.variable != "" {
+			v, ok := values[term.variable]
+			if !ok {
+				return 0, fmt.Errorf("No value for variable '%s'", term.variable)
+			}
+			val = v
+		}
+		res += term.sign * term.coef * val
+	}
+	return utils.RoundFloat(res, 10), nil
+}
+
+func templateVariables(tokens []templateToken) []string {
+	vars := []string{}
+	for _, token := range tokens {
+		for _, term := range token.expr {
+			if term.variable != "" {
+				vars = append(vars, term.variable)
+			}
+		}
+	}
+	return utils.UniqueElems(vars)
+}
+
+func substituteTokens(tokens []templateToken, values map[string]float64) (string, error) {
+	var b strings.Builder
+	openers := []int{}
+	skip := false
+	for _, token := range tokens {
+		if skip && token.kind != literalToken {
+			continue
+		}
+		skip = false
+		pos := b.Len()
+		if token.kind == openerToken {
+			openers = append(openers, pos)
+		}
+		if token.kind != leadToken {
+			b.WriteString(token.text)
+		}
+		if token.kind == closerToken && len(openers) > 0 {
+			pos = openers[len(openers)-1]
+			openers = openers[:len(openers)-1]
+		}
+		if token.expr == nil {
+			continue
+		}
+
+		val, err := evalExpression(token.expr, values)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case val < 0 && token.kind == leadToken:
+			return "", fmt.Errorf("Negative coefficient %v in the template", val)
+		case val < 0:
+			return "", fmt.Errorf("Negative subscript %v of '%s' in the template", val, token.text)
+		case val == 0 && token.kind == leadToken:
+			return "", fmt.Errorf("Zero coefficient in the template")
+		case val == 0:
+			res := b.String()[:pos]
+			b.Reset()
+			b.WriteString(res)
+			skip = token.kind == adductToken
+		case val != 1:
+			b.WriteString(strconv.FormatFloat(val, 'f', -1, 64))
+		}
+	}
+	return b.String(), nil
+}
+
+func SubstituteVariables(template string, values map[string]float64) (string, error) {
+	tokens, err := scanTemplate(template)
+	if err != nil {
+		return "", err
+	}
+	return substituteTokens(tokens, values)
+}
+
+func TemplateVariables(template string) ([]string, error) {
+	tokens, err := scanTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+	return templateVariables(tokens), nil
+}
+
+type FormulaTemplate struct {
+	template  string
+	tokens    []templateToken
+	variables []string
+}
+
+func NewFormulaTemplate(template string) (*FormulaTemplate, error) {
+	newTemplate := strings.Replace(template, " ", "", -1)
+	if newTemplate == "" {
+		return nil, fmt.Errorf("Empty formula template")
+	}
+	tokens, err := scanTemplate(newTemplate)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if token.kind == literalToken {
+			return nil, fmt.Errorf("There are invalid character(s) %s in the formula template '%s'",
+				token.text, newTemplate)
+		}
+	}
+
+	return &FormulaTemplate{
+		template:  newTemplate,
+		tokens:    tokens,
+		variables: templateVariables(tokens),
+	}, nil
+}
+
+func (t *FormulaTemplate) Template() string {
+	return t.template
+}
+
+func (t *FormulaTemplate) Variables() []string {
+	return t.variables
+}
+
+func (t *FormulaTemplate) Substitute(values map[string]float64) (string, error) {
+	return substituteTokens(t.tokens, values)
+}
+
+func (t *FormulaTemplate) Evaluate(values map[string]float64, precision ...uint) (*ChemicalFormula, error) {
+	formula, err := t.Substitute(values)
+	if err != nil {
+		return nil, err
+	}
+	return NewChemicalFormula(formula, precision...)
+}
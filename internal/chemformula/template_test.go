@@ -0,0 +1,84 @@
+package chemformula
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSubstituteVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		values   map[string]float64
+		expected string
+	}{
+		{"doping", "Ba1-xSrxTiO3", map[string]float64{"x": 0.1}, "Ba0.9Sr0.1TiO3"},
+		{"doping end member", "Ba1-xSrxTiO3", map[string]float64{"x": 0}, "BaTiO3"},
+		{"oxygen deficiency", "YBa2Cu3O7-δ", map[string]float64{"δ": 0.15}, "YBa2Cu3O6.85"},
+		{"two variables", "La1-xSrxMnO3-d", map[string]float64{"x": 0.3, "d": 0.05}, "La0.7Sr0.3MnO2.95"},
+		{"multiplier", "Li2xNi2-2xO2", map[string]float64{"x": 0.5}, "LiNiO2"},
+		{"bracket group", "Ca(OH)2x(CO3)1-x", map[string]float64{"x": 0}, "Ca(CO3)"},
+		{"adduct", "CuSO4*xH2O", map[string]float64{"x": 0}, "CuSO4"},
+		{"reaction", "2-2xNiO+xLi2CO3=Li2xNi2-2xO2", map[string]float64{"x": 0.1}, "1.8NiO+0.1Li2CO3=Li0.2Ni1.8O2"},
+		{"no variables", "Fe3O4->Fe", nil, "Fe3O4->Fe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SubstituteVariables(tt.template, tt.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.expected {
+				t.Errorf("SubstituteVariables() = %s, expected %s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSubstituteVariables_errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		values   map[string]float64
+		expected string
+	}{
+		{"negative", "Ba1-xSrxTiO3", map[string]float64{"x": 2}, "Negative subscript -1 of 'Ba' in the template"},
+		{"no value", "Ba1-xSrxTiO3", nil, "No value for variable 'x'"},
+		{"invalid expression", "Ba1-x2SrxTiO3", nil, "Invalid subscript expression at position 5 of the template"},
+		{"zero coefficient", "xBaO+TiO2=BaTiO3", map[string]float64{"x": 0}, "Zero coefficient in the template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SubstituteVariables(tt.template, tt.values)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("SubstituteVariables() error = %v, expected %s", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormulaTemplate(t *testing.T) {
+	tmpl, err := NewFormulaTemplate("La1-xSrxMnO3-δ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars := tmpl.Variables(); !slices.Equal(vars, []string{"x", "δ"}) {
+		t.Errorf("Variables() = %v, expected [x δ]", vars)
+	}
+	form, err := tmpl.Evaluate(map[string]float64{"x": 0.2, "δ": 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Atom{
+		{Label: "La", Amount: 0.8},
+		{Label: "Sr", Amount: 0.2},
+		{Label: "Mn", Amount: 1},
+		{Label: "O", Amount: 2.9}}
+	if !slices.Equal(form.ParsedFormula(), expected) {
+		t.Errorf("ParsedFormula() = %v, expected %v", form.ParsedFormula(), expected)
+	}
+	_, err = NewFormulaTemplate("BaTiO3+TiO2")
+	if err == nil {
+		t.Errorf("NewFormulaTemplate() should fail on reaction string")
+	}
+}
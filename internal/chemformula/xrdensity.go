@@ -0,0 +1,143 @@
+package chemformula
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+const avogadroConstant = 6.02214076e23
+
+type UnitCell struct {
+	A     float64
+	B     float64
+	C     float64
+	Alpha float64
+	Beta  float64
+	Gamma float64
+}
+
+func (u UnitCell) Volume() (float64, error) {
+	if u.A <= 0 || u.B <= 0 || u.C <= 0 {
+		return 0, fmt.Errorf("Lattice parameters a = %v, b = %v, c = %v should be > 0", u.A, u.B, u.C)
+	}
+	cosA := math.Cos(u.Alpha * math.Pi / 180)
+	cosB := math.Cos(u.Beta * math.Pi / 180)
+	cosG := math.Cos(u.Gamma * math.Pi / 180)
+	root := 1 - cosA*cosA - cosB*cosB - cosG*cosG + 2*cosA*cosB*cosG
+	if root <= 0 {
+		return 0, fmt.Errorf("Invalid cell angles α = %v, β = %v, γ = %v", u.Alpha, u.Beta, u.Gamma)
+	}
+	return u.A * u.B * u.C * math.Sqrt(root), nil
+}
+
+type XRayDensity struct {
+	formula   *ChemicalFormula
+	z         float64
+	volume    float64
+	precision uint
+}
+
+func NewXRayDensity(formula string, z float64, cell UnitCell, precision ...uint) (*XRayDensity, error) {
+	volume, err := cell.Volume()
+	if err != nil {
+		return nil, err
+	}
+	return NewXRayDensityFromVolume(formula, z, volume, precision...)
+}
+
+func NewXRayDensityFromVolume(formula string, z float64, volume float64, precision ...uint) (*XRayDensity, error) {
+	var prec uint = 8
+	if len(precision) > 0 {
+		prec = precision[0]
+	}
+
+	form, err := NewChemicalFormula(formula, prec)
+	if err != nil {
+		return nil, err
+	}
+	if z < 0 {
+		return nil, fmt.Errorf("Negative number of formula units Z = %v", z)
+	}
+	if volume <= 0 {
+		return nil, fmt.Errorf("Cell volume %v should be > 0", volume)
+	}
+
+	return &XRayDensity{
+		formula:   form,
+		z:         z,
+		volume:    volume,
+		precision: prec,
+	}, nil
+}
+
+func (x *XRayDensity) CellVolume() float64 {
+	return utils.RoundFloat(x.volume, x.precision)
+}
+
+func (x *XRayDensity) densityPerFormulaUnit() float64 {
+	return molarMass{x.formula.ParsedFormula()}.molarMass() / (avogadroConstant * x.volume * 1e-24)
+}
+
+func (x *XRayDensity) Density() (float64, error) {
+	if x.z == 0 {
+		return 0, fmt.Errorf("Number of formula units Z is not set")
+	}
+	return utils.RoundFloat(x.z*x.densityPerFormulaUnit(), x.precision), nil
+}
+
+func (x *XRayDensity) FormulaUnits(density float64) (float64, error) {
+	if density <= 0 {
+		return 0, fmt.Errorf("Density %v should be > 0", density)
+	}
+	return utils.RoundFloat(density/x.densityPerFormulaUnit(), x.precision), nil
+}
+
+func (x *XRayDensity) RelativeDensity(measured float64) (float64, error) {
+	if measured < 0 {
+		return 0, fmt.Errorf("Negative density %v", measured)
+	}
+	density, err := x.Density()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(measured/density*100, x.precision), nil
+}
+
+func (x *XRayDensity) Output(printPrecision ...uint) (xdOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	density, err := x.Density()
+	if err != nil {
+		return xdOutput{}, err
+	}
+	return xdOutput{
+		Formula:    x.formula.Formula(),
+		MolarMass:  utils.RoundFloat(x.formula.MolarMass(), pPrecision),
+		Z:          x.z,
+		CellVolume: utils.RoundFloat(x.volume, pPrecision),
+		Density:    utils.RoundFloat(density, pPrecision),
+	}, nil
+}
+
+type xdOutput struct {
+	Formula    string
+	MolarMass  float64
+	Z          float64
+	CellVolume float64
+	Density    float64
+}
+
+func (o xdOutput) String() string {
+	return fmt.Sprintln("formula:", o.Formula) +
+		fmt.Sprintln("molar mass:", o.MolarMass) +
+		fmt.Sprintln("Z:", o.Z) +
+		fmt.Sprintf("cell volume: %v Å3\n", o.CellVolume) +
+		fmt.Sprintf("X-ray density: %v g/cm3", o.Density)
+}
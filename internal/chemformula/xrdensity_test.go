@@ -0,0 +1,73 @@
+package chemformula
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUnitCell_Volume(t *testing.T) {
+	tests := []struct {
+		name     string
+		cell     UnitCell
+		expected float64
+	}{
+		{"cubic", UnitCell{4, 4, 4, 90, 90, 90}, 64},
+		{"hexagonal", UnitCell{3, 3, 5, 90, 90, 120}, 38.97114317},
+		{"triclinic", UnitCell{5, 6, 7, 80, 85, 95}, 204.89974},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cell.Volume()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tt.expected) > 1e-3 {
+				t.Errorf("Volume() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+	if _, err := (UnitCell{1, 1, 1, 130, 130, 130}).Volume(); err == nil {
+		t.Error("expected error for invalid angles")
+	}
+}
+
+func TestXRayDensity(t *testing.T) {
+	x, err := NewXRayDensity("NaCl", 4, UnitCell{5.6402, 5.6402, 5.6402, 90, 90, 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	density, err := x.Density()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(density-2.1641) > 1e-3 {
+		t.Errorf("Density() = %v, expected 2.1641", density)
+	}
+	z, err := x.FormulaUnits(2.165)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(z-4) > 0.01 {
+		t.Errorf("FormulaUnits() = %v, expected 4", z)
+	}
+	rel, err := x.RelativeDensity(density * 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(rel-95) > 1e-6 {
+		t.Errorf("RelativeDensity() = %v, expected 95", rel)
+	}
+}
+
+func TestXRayDensity_noZ(t *testing.T) {
+	x, err := NewXRayDensityFromVolume("NaCl", 0, 179.43)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := x.Density(); err == nil {
+		t.Error("expected error for unset Z")
+	}
+	if _, err := NewXRayDensityFromVolume("NaCl", 4, 0); err == nil {
+		t.Error("expected error for zero volume")
+	}
+}
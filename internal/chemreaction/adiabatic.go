@@ -0,0 +1,228 @@
+package chemreaction
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"text/tabwriter"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+const (
+	selfPropagatingTemperature = 1800.0
+	maxAdiabaticTemperature    = 6000.0
+)
+
+type Diluent struct {
+	Formula      string
+	Phase        string
+	Moles        float64
+	MassFraction float64
+}
+
+type Adiabatic struct {
+	thermo   *ReactionThermo
+	initial  float64
+	diluents []Diluent
+	dilData  []ThermoData
+	dilMoles []float64
+}
+
+func NewAdiabatic(reaction *ChemicalReaction, table *ThermoTable, phases map[string]string, initial float64, diluents ...Diluent) (*Adiabatic, error) {
+	if initial < standardTemperature {
+		return nil, fmt.Errorf("initial temperature %v K should be >= %v K", initial, standardTemperature)
+	}
+	rt, err := NewReactionThermo(reaction, table, phases)
+	if err != nil {
+		return nil, err
+	}
+	molars, err := reaction.MolarMasses()
+	if err != nil {
+		return nil, err
+	}
+	mass := 0.0
+	for i := range reaction.decomposer.reactants {
+		mass -= rt.coefs[i] * molars[i]
+	}
+
+	dilData := make([]ThermoData, len(diluents))
+	dilMoles := make([]float64, len(diluents))
+	dilMolars := make([]float64, len(diluents))
+	fraction := 0.0
+	for i, diluent := range diluents {
+		if diluent.Moles < 0 || diluent.MassFraction < 0 {
+			return nil, fmt.Errorf("negative amount of the diluent %s", diluent.Formula)
+		}
+		if (diluent.Moles > 0) == (diluent.MassFraction > 0) {
+			return nil, fmt.Errorf("either moles or mass fraction of the diluent %s should be given", diluent.Formula)
+		}
+		dilData[i], err = table.Lookup(diluent.Formula, diluent.Phase)
+		if err != nil {
+			return nil, err
+		}
+		f, err := chemformula.NewChemicalFormula(diluent.Formula)
+		if err != nil {
+			return nil, err
+		}
+		dilMolars[i] = f.MolarMass()
+		dilMoles[i] = diluent.Moles
+		mass += diluent.Moles * dilMolars[i]
+		fraction += diluent.MassFraction
+	}
+	if fraction >= 1 {
+		return nil, fmt.Errorf("sum of the diluent mass fractions %v should be < 1", fraction)
+	}
+	total := mass / (1 - fraction)
+	for i, diluent := range diluents {
+		if diluent.MassFraction > 0 {
+			dilMoles[i] = total * diluent.MassFraction / dilMolars[i]
+		}
+	}
+
+	return &Adiabatic{
+		thermo:   rt,
+		initial:  initial,
+		diluents: diluents,
+		dilData:  dilData,
+		dilMoles: dilMoles,
+	}, nil
+}
+
+func (a *Adiabatic) Thermo() *ReactionThermo {
+	return a.thermo
+}
+
+func (a *Adiabatic) DiluentMoles() []float64 {
+	return utils.RoundFloatS(a.dilMoles, a.thermo.precision)
+}
+
+func (a *Adiabatic) balance(temperature float64) float64 {
+	balance := 0.0
+	for i, d := range a.thermo.data {
+		if a.thermo.coefs[i] < 0 {
+			balance += a.thermo.coefs[i] * d.Enthalpy(a.initial)
+		} else {
+			balance += a.thermo.coefs[i] * d.Enthalpy(temperature)
+		}
+	}
+	for i, d := range a.dilData {
+		balance += a.dilMoles[i] * (d.Enthalpy(temperature) - d.Enthalpy(a.initial))
+	}
+	return balance
+}
+
+func (a *Adiabatic) ReactionHeat() float64 {
+	return utils.RoundFloat(-a.balance(a.initial), a.thermo.precision)
+}
+
+func (a *Adiabatic) Temperature() (float64, error) {
+	low, high := a.initial, maxAdiabaticTemperature
+	if a.balance(low) >= 0 {
+		return 0, fmt.Errorf("reaction %s is not exothermic at %v K", a.thermo.reaction.reaction, a.initial)
+	}
+	if a.balance(high) < 0 {
+		return 0, fmt.Errorf("adiabatic temperature is above %v K", maxAdiabaticTemperature)
+	}
+	for high-low > 1e-9 {
+		mid := (low + high) / 2
+		if a.balance(mid) < 0 {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return utils.RoundFloat((low+high)/2, a.thermo.precision), nil
+}
+
+func (a *Adiabatic) MeltedFraction() (map[string]float64, error) {
+	tad, err := a.Temperature()
+	if err != nil {
+		return nil, err
+	}
+	melted := map[string]float64{}
+	for i, d := range a.thermo.data {
+		if a.thermo.coefs[i] <= 0 || d.Tm <= standardTemperature {
+			continue
+		}
+		switch {
+		case tad > d.Tm+1e-6:
+			melted[d.Formula] = 1
+		case math.Abs(tad-d.Tm) <= 1e-6 && d.Hm > 0:
+			fraction := -a.balance(d.Tm) / (a.thermo.coefs[i] * d.Hm)
+			melted[d.Formula] = utils.RoundFloat(math.Max(math.Min(fraction, 1), 0), a.thermo.precision)
+		default:
+			melted[d.Formula] = 0
+		}
+	}
+	return melted, nil
+}
+
+func (a *Adiabatic) IsSelfPropagating() (bool, error) {
+	tad, err := a.Temperature()
+	if err != nil {
+		return false, err
+	}
+	return tad > selfPropagatingTemperature, nil
+}
+
+func (a *Adiabatic) Output(printPrecision ...uint) (adOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	final, err := a.thermo.reaction.FinalReaction()
+	if err != nil {
+		return adOutput{}, err
+	}
+	tad, err := a.Temperature()
+	if err != nil {
+		return adOutput{}, err
+	}
+	diluents := make([]string, len(a.diluents))
+	for i, d := range a.dilData {
+		diluents[i] = d.Formula
+	}
+
+	return adOutput{
+		FinalReaction:   final,
+		Initial:         a.initial,
+		ReactionHeat:    utils.RoundFloat(a.ReactionHeat(), pPrecision),
+		Diluents:        diluents,
+		DiluentMoles:    utils.RoundFloatS(a.dilMoles, pPrecision),
+		Temperature:     utils.RoundFloat(tad, pPrecision),
+		SelfPropagating: tad > selfPropagatingTemperature,
+	}, nil
+}
+
+type adOutput struct {
+	FinalReaction   string
+	Initial         float64
+	ReactionHeat    float64
+	Diluents        []string
+	DiluentMoles    []float64
+	Temperature     float64
+	SelfPropagating bool
+}
+
+func (o adOutput) String() string {
+	out := fmt.Sprintln("final reaction:", o.FinalReaction) +
+		fmt.Sprintf("initial temperature: %v K\n", o.Initial) +
+		fmt.Sprintf("heat of reaction: %v kJ\n", o.ReactionHeat)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	for i, diluent := range o.Diluents {
+		fmt.Fprintf(w, "diluent\t%s\tn = %v\tmol\n", diluent, o.DiluentMoles[i])
+	}
+
+	w.Flush()
+	return out + buf.String() +
+		fmt.Sprintf("adiabatic temperature: %v K\n", o.Temperature) +
+		fmt.Sprintf("self-propagating (Tad > %v K): %v", selfPropagatingTemperature, o.SelfPropagating)
+}
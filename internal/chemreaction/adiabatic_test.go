@@ -0,0 +1,150 @@
+package chemreaction
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func thermiteTable(t *testing.T) *ThermoTable {
+	table, err := NewThermoTable([]ThermoData{
+		{Formula: "Fe2O3", Phase: "s", Hf: -824.2, S: 87.4},
+		{Formula: "Al", Phase: "s", Hf: 0, S: 28.3},
+		{Formula: "Al2O3", Phase: "s", Hf: -1675.7, S: 50.9, Cp: []float64{120}},
+		{Formula: "Fe", Phase: "s", Hf: 0, S: 27.3, Cp: []float64{40}, Tm: 1811, Hm: 13.8},
+		{Formula: "CaCO3", Phase: "s", Hf: -1207.6, S: 91.7},
+		{Formula: "CaO", Phase: "s", Hf: -634.9, S: 38.1},
+		{Formula: "CO2", Phase: "g", Hf: -393.5, S: 213.8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestAdiabatic(t *testing.T) {
+	table := thermiteTable(t)
+	tests := []struct {
+		name     string
+		diluents []Diluent
+		tad      float64
+		melted   float64
+	}{
+		{"no diluent", nil, 1811 + (851.5-0.2*1512.85-27.6)/0.2, 1},
+		{"2 mol of Al2O3", []Diluent{{Formula: "Al2O3", Moles: 2}}, 1811 + (851.5-0.44*1512.85-27.6)/0.44, 1},
+		{"3 mol of Al2O3", []Diluent{{Formula: "Al2O3", Moles: 3}}, 1811, (851.5 - 0.56*1512.85) / 27.6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaction, err := NewChemicalReaction("Fe2O3+Al=Al2O3+Fe")
+			if err != nil {
+				t.Fatal(err)
+			}
+			ad, err := NewAdiabatic(reaction, table, nil, standardTemperature, tt.diluents...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if heat := ad.ReactionHeat(); math.Abs(heat-851.5) > 1e-6 {
+				t.Errorf("ReactionHeat() = %v, expected 851.5", heat)
+			}
+			tad, err := ad.Temperature()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(tad-tt.tad) > 1e-6 {
+				t.Errorf("Temperature() = %v, expected %v", tad, tt.tad)
+			}
+			melted, err := ad.MeltedFraction()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(melted["Fe"]-tt.melted) > 1e-6 {
+				t.Errorf("MeltedFraction() = %v, expected %v", melted["Fe"], tt.melted)
+			}
+			shs, err := ad.IsSelfPropagating()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if shs != (tt.tad > 1800) {
+				t.Errorf("IsSelfPropagating() = %v", shs)
+			}
+		})
+	}
+}
+
+func TestAdiabaticMassFraction(t *testing.T) {
+	table := thermiteTable(t)
+	reaction, err := NewChemicalReaction("Fe2O3+2Al=Al2O3+2Fe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	molars, err := reaction.MolarMasses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixture := molars[0] + 2*molars[1]
+	diluent := 3 * molars[2]
+	ad, err := NewAdiabatic(reaction, table, nil, standardTemperature,
+		Diluent{Formula: "Al2O3", MassFraction: diluent / (mixture + diluent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moles := ad.DiluentMoles(); math.Abs(moles[0]-3) > 1e-6 {
+		t.Errorf("DiluentMoles() = %v, expected [3]", moles)
+	}
+
+	out, err := ad.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "final reaction: Fe2O3+2Al=Al2O3+2Fe\n" +
+		"initial temperature: 298.15 K\n" +
+		"heat of reaction: 851.5 kJ\n" +
+		"diluent  Al2O3  n = 3  mol\n" +
+		"adiabatic temperature: 1811 K\n" +
+		"self-propagating (Tad > 1800 K): true"
+	if out.String() != expected {
+		t.Errorf("Output() = \n%s\nexpected\n%s", out, expected)
+	}
+}
+
+func TestAdiabaticErrors(t *testing.T) {
+	table := thermiteTable(t)
+	tests := []struct {
+		name     string
+		reaction string
+		initial  float64
+		diluents []Diluent
+		message  string
+	}{
+		{"cold start", "Fe2O3+Al=Al2O3+Fe", 200, nil, "initial temperature"},
+		{"no amount", "Fe2O3+Al=Al2O3+Fe", standardTemperature, []Diluent{{Formula: "Al2O3"}}, "either moles"},
+		{"too much", "Fe2O3+Al=Al2O3+Fe", standardTemperature, []Diluent{{Formula: "Al2O3", MassFraction: 1}}, "mass fractions"},
+		{"unknown diluent", "Fe2O3+Al=Al2O3+Fe", standardTemperature, []Diluent{{Formula: "MgO", Moles: 1}}, "no thermochemical data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reaction, err := NewChemicalReaction(tt.reaction)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = NewAdiabatic(reaction, table, nil, tt.initial, tt.diluents...)
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("expected error with '%s', got %v", tt.message, err)
+			}
+		})
+	}
+
+	reaction, err := NewChemicalReaction("CaCO3=CaO+CO2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ad, err := NewAdiabatic(reaction, table, nil, standardTemperature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ad.Temperature()
+	if err == nil {
+		t.Error("expected an error for an endothermic reaction")
+	}
+}
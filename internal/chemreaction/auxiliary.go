@@ -0,0 +1,118 @@
+package chemreaction
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+const (
+	citricAcid     = "C6H8O7"
+	ethyleneGlycol = "C2H6O2"
+	edta           = "C10H16N2O8"
+	ammonia        = "NH3"
+)
+
+type AuxiliaryReagent struct {
+	Formula string
+	Ratio   float64
+	Base    string
+}
+
+func PechiniReagents(caRatio float64, egRatio float64) []AuxiliaryReagent {
+	return []AuxiliaryReagent{
+		{Formula: citricAcid, Ratio: caRatio},
+		{Formula: ethyleneGlycol, Ratio: egRatio, Base: citricAcid},
+	}
+}
+
+func EDTACitrateReagents(edtaRatio float64, caRatio float64, nh3Ratio float64) []AuxiliaryReagent {
+	return []AuxiliaryReagent{
+		{Formula: edta, Ratio: edtaRatio},
+		{Formula: citricAcid, Ratio: caRatio},
+		{Formula: ammonia, Ratio: nh3Ratio, Base: edta},
+	}
+}
+
+func (r *ChemicalReaction) SetAuxiliaryReagents(reagents []AuxiliaryReagent) error {
+	formulas := []string{}
+	for _, reagent := range reagents {
+		_, err := chemformula.NewChemicalFormula(reagent.Formula)
+		if err != nil {
+			return err
+		}
+		if reagent.Ratio <= 0 {
+			return fmt.Errorf("ratio %v of %s should be > 0", reagent.Ratio, reagent.Formula)
+		}
+		if reagent.Base != "" && !slices.Contains(formulas, strings.Replace(reagent.Base, " ", "", -1)) {
+			return fmt.Errorf("base %s of %s should be one of the previous auxiliary reagents", reagent.Base, reagent.Formula)
+		}
+		formulas = append(formulas, strings.Replace(reagent.Formula, " ", "", -1))
+	}
+	r.auxiliary = reagents
+	return nil
+}
+
+func (r *ChemicalReaction) AuxiliaryReagents() []AuxiliaryReagent {
+	return r.auxiliary
+}
+
+func (r *ChemicalReaction) MetalMoles() (float64, error) {
+	parsed, err := r.ParsedFormulas()
+	if err != nil {
+		return 0, err
+	}
+	masses, err := r.Masses()
+	if err != nil {
+		return 0, err
+	}
+	molars, err := r.MolarMasses()
+	if err != nil {
+		return 0, err
+	}
+	moles := 0.0
+	for i := range r.decomposer.reactants {
+		metals := 0.0
+		for _, atom := range parsed[i] {
+			if chemformula.IsMetal(atom.Label) {
+				metals += atom.Amount
+			}
+		}
+		moles += masses[i] / molars[i] * metals
+	}
+	return utils.RoundFloat(moles, r.reacOpts.Precision), nil
+}
+
+func (r *ChemicalReaction) auxiliaryMoles() ([]float64, error) {
+	metalMoles, err := r.MetalMoles()
+	if err != nil {
+		return nil, err
+	}
+	moles := make([]float64, len(r.auxiliary))
+	for i, reagent := range r.auxiliary {
+		base := metalMoles
+		for j := range i {
+			if strings.Replace(r.auxiliary[j].Formula, " ", "", -1) == strings.Replace(reagent.Base, " ", "", -1) {
+				base = moles[j]
+			}
+		}
+		moles[i] = base * reagent.Ratio
+	}
+	return moles, nil
+}
+
+func (r *ChemicalReaction) AuxiliaryMasses() ([]float64, error) {
+	moles, err := r.auxiliaryMoles()
+	if err != nil {
+		return nil, err
+	}
+	masses := make([]float64, len(moles))
+	for i, reagent := range r.auxiliary {
+		f, _ := chemformula.NewChemicalFormula(reagent.Formula)
+		masses[i] = utils.RoundFloat(moles[i]*f.MolarMass(), r.reacOpts.Precision)
+	}
+	return masses, nil
+}
@@ -0,0 +1,62 @@
+package chemreaction
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestChemicalReaction_AuxiliaryMasses(t *testing.T) {
+	reac, err := NewChemicalReaction("La(NO3)3*6H2O+Fe(NO3)3*9H2O=LaFeO3+NO2+O2+H2O")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = reac.SetAuxiliaryReagents(PechiniReagents(1.5, 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	metals, err := reac.MetalMoles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := 2 / 242.7475
+	if math.Abs(metals-expected) > 1e-6 {
+		t.Errorf("MetalMoles() = %v, expected %v", metals, expected)
+	}
+	masses, err := reac.AuxiliaryMasses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca := expected * 1.5 * 192.123
+	eg := expected * 1.5 * 4 * 62.068
+	if math.Abs(masses[0]-ca) > 1e-4 || math.Abs(masses[1]-eg) > 1e-4 {
+		t.Errorf("AuxiliaryMasses() = %v, expected [%v %v]", masses, ca, eg)
+	}
+
+	out, err := reac.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "C2H6O2         M = 62.068    g/mol  m = 3.0683  g  (auxiliary)") {
+		t.Errorf("Output() = %s", out)
+	}
+}
+
+func TestChemicalReaction_SetAuxiliaryReagents(t *testing.T) {
+	reac, _ := NewChemicalReaction("La(NO3)3*6H2O+Fe(NO3)3*9H2O=LaFeO3+NO2+O2+H2O")
+	tests := []struct {
+		name     string
+		reagents []AuxiliaryReagent
+	}{
+		{"invalid formula", []AuxiliaryReagent{{Formula: "Xx", Ratio: 1}}},
+		{"zero ratio", []AuxiliaryReagent{{Formula: "C6H8O7", Ratio: 0}}},
+		{"unknown base", []AuxiliaryReagent{{Formula: "C2H6O2", Ratio: 1, Base: "C6H8O7"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := reac.SetAuxiliaryReagents(tt.reagents); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
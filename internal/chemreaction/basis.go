@@ -0,0 +1,230 @@
+package chemreaction
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+	"gonum.org/v1/gonum/mat"
+)
+
+type ReactionBasis struct {
+	species  []string
+	matrix   *mat.Dense
+	reacOpts ReacOptions
+	pivots   *[]int
+	vectors  *[][]float64
+}
+
+func NewReactionBasis(species []string, options ...ReacOptions) (*ReactionBasis, error) {
+	if len(species) < 2 {
+		return nil, fmt.Errorf("at least two species are required, got %d", len(species))
+	}
+	newSpecies := make([]string, len(species))
+	parsed := make([][]chemformula.Atom, len(species))
+	for i, s := range species {
+		newSpecies[i] = strings.Replace(s, " ", "", -1)
+		if slices.Contains(newSpecies[:i], newSpecies[i]) {
+			return nil, fmt.Errorf("duplicate species %s", newSpecies[i])
+		}
+		f, err := chemformula.NewChemicalFormula(newSpecies[i])
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = f.ParsedFormula()
+	}
+
+	var reacOpt ReacOptions
+	if options == nil {
+		reacOpt = ReacOptions{
+			Rmode:      Force,
+			Target:     0,
+			TargerMass: 1.0,
+			Intify:     true,
+			Precision:  8,
+			Tolerance:  1e-8,
+		}
+	} else {
+		reacOpt = options[0]
+	}
+	reacOpt.Rmode = Force
+	reacOpt.Target = 0
+
+	return &ReactionBasis{
+		species:  newSpecies,
+		matrix:   createReacMatrix(parsed),
+		reacOpts: reacOpt,
+	}, nil
+}
+
+func (b *ReactionBasis) Species() []string {
+	return b.species
+}
+
+func (b *ReactionBasis) Matrix() *mat.Dense {
+	return b.matrix
+}
+
+func (b *ReactionBasis) Rank() (int, error) {
+	rank, _, err := matrixRank(b.matrix, b.reacOpts.Tolerance)
+	return rank, err
+}
+
+func (b *ReactionBasis) Count() (int, error) {
+	rank, err := b.Rank()
+	if err != nil {
+		return 0, err
+	}
+	return len(b.species) - rank, nil
+}
+
+func (b *ReactionBasis) rref() ([]int, *mat.Dense) {
+	r := mat.DenseCopyOf(b.matrix)
+	rows, cols := r.Dims()
+	pivots := []int{}
+	row := 0
+	for col := 0; col < cols && row < rows; col++ {
+		best := row
+		for i := row + 1; i < rows; i++ {
+			if math.Abs(r.At(i, col)) > math.Abs(r.At(best, col)) {
+				best = i
+			}
+		}
+		if math.Abs(r.At(best, col)) <= b.reacOpts.Tolerance {
+			continue
+		}
+		if best != row {
+			bestRow := mat.Row(nil, best, r)
+			r.SetRow(best, mat.Row(nil, row, r))
+			r.SetRow(row, bestRow)
+		}
+		pivot := r.At(row, col)
+		for j := range cols {
+			r.Set(row, j, r.At(row, j)/pivot)
+		}
+		for i := range rows {
+			if i == row || r.At(i, col) == 0 {
+				continue
+			}
+			factor := r.At(i, col)
+			for j := range cols {
+				r.Set(i, j, r.At(i, j)-factor*r.At(row, j))
+			}
+		}
+		pivots = append(pivots, col)
+		row++
+	}
+	return pivots, r
+}
+
+func intifyVector(vector []float64, maxDenom int64) ([]float64, error) {
+	fractions := make([]utils.SimpleFraction, len(vector))
+	denominators := make([]int64, len(vector))
+	for i, v := range vector {
+		fractions[i] = utils.NewSimpleFraction(v, maxDenom)
+		denominators[i] = fractions[i].Den
+	}
+	lcm := utils.FindLCMSliceInt64(denominators)
+	if lcm < 0 {
+		return nil, fmt.Errorf("can't convert %v to integer coefficients", vector)
+	}
+	vals := make([]int64, len(fractions))
+	for i, frac := range fractions {
+		vals[i] = frac.Num * (lcm / frac.Den)
+	}
+	gcd := utils.FindGCDSliceInt64(vals)
+	result := make([]float64, len(vals))
+	for i, val := range vals {
+		result[i] = float64(val / gcd)
+	}
+	return result, nil
+}
+
+func (b *ReactionBasis) Vectors() ([][]float64, error) {
+	if b.vectors == nil {
+		pivots, r := b.rref()
+		vectors := [][]float64{}
+		for col := range b.species {
+			if slices.Contains(pivots, col) {
+				continue
+			}
+			vector := make([]float64, len(b.species))
+			vector[col] = 1
+			for row, pivot := range pivots {
+				vector[pivot] = -r.At(row, col)
+			}
+			intVector, err := intifyVector(vector, 1_000_000)
+			if err != nil {
+				return nil, err
+			}
+			vectors = append(vectors, intVector)
+		}
+		b.pivots = &pivots
+		b.vectors = &vectors
+	}
+	return *b.vectors, nil
+}
+
+func (b *ReactionBasis) Dependent() ([]string, error) {
+	_, err := b.Vectors()
+	if err != nil {
+		return nil, err
+	}
+	dependent := []string{}
+	for col, species := range b.species {
+		if !slices.Contains(*b.pivots, col) {
+			dependent = append(dependent, species)
+		}
+	}
+	return dependent, nil
+}
+
+func (b *ReactionBasis) equation(vector []float64) string {
+	sides := [2][]string{}
+	for i, coef := range vector {
+		if coef == 0 {
+			continue
+		}
+		side := 1
+		if coef < 0 {
+			side = 0
+		}
+		term := b.species[i]
+		if math.Abs(coef) != 1 {
+			term = formatFloat(math.Abs(coef)) + term
+		}
+		sides[side] = append(sides[side], term)
+	}
+	return strings.Join(sides[0], reactionRegexes.reactantSeparator) + "=" +
+		strings.Join(sides[1], reactionRegexes.reactantSeparator)
+}
+
+func (b *ReactionBasis) Equations() ([]string, error) {
+	vectors, err := b.Vectors()
+	if err != nil {
+		return nil, err
+	}
+	equations := make([]string, len(vectors))
+	for i, vector := range vectors {
+		equations[i] = b.equation(vector)
+	}
+	return equations, nil
+}
+
+func (b *ReactionBasis) Reactions() ([]*ChemicalReaction, error) {
+	equations, err := b.Equations()
+	if err != nil {
+		return nil, err
+	}
+	reactions := make([]*ChemicalReaction, len(equations))
+	for i, equation := range equations {
+		reactions[i], err = newChemicalReaction(equation, true, b.reacOpts)
+		if err != nil {
+			return nil, fmt.Errorf("can't create the reaction %s: %w", equation, err)
+		}
+	}
+	return reactions, nil
+}
@@ -0,0 +1,115 @@
+package chemreaction
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReactionBasis(t *testing.T) {
+	tests := []struct {
+		name      string
+		species   []string
+		count     int
+		dependent []string
+		equations []string
+	}{
+		{"carbon oxides", []string{"C", "O2", "CO", "CO2"}, 2, []string{"CO", "CO2"},
+			[]string{"2C+O2=2CO", "C+O2=CO2"}},
+		{"iron oxides", []string{"Fe", "FeO", "Fe2O3", "Fe3O4", "O2"}, 3, []string{"Fe2O3", "Fe3O4", "O2"},
+			[]string{"3FeO=Fe+Fe2O3", "4FeO=Fe+Fe3O4", "2FeO=2Fe+O2"}},
+		{"independent", []string{"BaO", "TiO2"}, 0, []string{}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			basis, err := NewReactionBasis(tt.species)
+			if err != nil {
+				t.Fatal(err)
+			}
+			count, err := basis.Count()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != tt.count {
+				t.Errorf("Count() = %d, expected %d", count, tt.count)
+			}
+			dependent, err := basis.Dependent()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(dependent, tt.dependent) {
+				t.Errorf("Dependent() = %v, expected %v", dependent, tt.dependent)
+			}
+			equations, err := basis.Equations()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(equations, tt.equations) {
+				t.Errorf("Equations() = %v, expected %v", equations, tt.equations)
+			}
+			reactions, err := basis.Reactions()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, reaction := range reactions {
+				final, err := reaction.FinalReaction()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if final != tt.equations[i] || !reaction.IsBalanced() {
+					t.Errorf("reaction %s is not equal to %s or is not balanced", final, tt.equations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReactionBasisErrors(t *testing.T) {
+	for _, species := range [][]string{{"O2"}, {"O2", "O2"}, {"O2", "Xx"}} {
+		_, err := NewReactionBasis(species)
+		if err == nil {
+			t.Errorf("expected an error for %v", species)
+		}
+	}
+
+	basis, err := NewReactionBasis([]string{"O2", "O3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	equations, err := basis.Equations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(equations, []string{"3O2=2O3"}) {
+		t.Errorf("Equations() = %v, expected [3O2=2O3]", equations)
+	}
+	reactions, err := basis.Reactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	coefs, err := reactions[0].Coefficients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(coefs.Result, []float64{3, 2}) {
+		t.Errorf("Coefficients() = %v, expected [3 2]", coefs.Result)
+	}
+
+	basis, err = NewReactionBasis([]string{"NO2", "N2O4", "N2", "O2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reactions, err = basis.Reactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reactions) != 2 {
+		t.Fatalf("Reactions() returned %d reactions, expected 2", len(reactions))
+	}
+	coefs, err = reactions[0].Coefficients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(coefs.Result, []float64{2, 1}) {
+		t.Errorf("Coefficients() of 2NO2=N2O4 = %v, expected [2 1]", coefs.Result)
+	}
+}
@@ -0,0 +1,382 @@
+package chemreaction
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+var propellantValences = map[string]float64{
+	"C": 4,
+	"H": 1,
+	"O": -2,
+	"N": 0,
+}
+
+const nitrateNitrogenValence = 5
+
+type Combustion struct {
+	target     string
+	precursors []string
+	fuel       string
+	phi        float64
+	valences   map[string]float64
+	reacOpts   ReacOptions
+	coefs      *[]float64
+	reaction   *ChemicalReaction
+}
+
+func NewCombustion(target string, precursors []string, fuel string, phi float64, options ...ReacOptions) (*Combustion, error) {
+	if phi <= 0 {
+		return nil, fmt.Errorf("equivalence ratio %v should be > 0", phi)
+	}
+	if len(precursors) == 0 {
+		return nil, fmt.Errorf("no precursors for the combustion synthesis")
+	}
+	newTarget := strings.Replace(target, " ", "", -1)
+	newFuel := strings.Replace(fuel, " ", "", -1)
+	newPrecursors := make([]string, len(precursors))
+	for i, formula := range append([]string{newTarget, newFuel}, precursors...) {
+		_, err := chemformula.NewChemicalFormula(formula)
+		if err != nil {
+			return nil, err
+		}
+		if i > 1 {
+			newPrecursors[i-2] = strings.Replace(formula, " ", "", -1)
+		}
+	}
+
+	var reacOpt ReacOptions
+	if options == nil {
+		reacOpt = ReacOptions{
+			Rmode:      Balance,
+			Target:     0,
+			TargerMass: 1.0,
+			Intify:     true,
+			Precision:  8,
+			Tolerance:  1e-8,
+		}
+	} else {
+		reacOpt = options[0]
+	}
+	reacOpt.Rmode = Force
+	reacOpt.Target = 0
+
+	return &Combustion{
+		target:     newTarget,
+		precursors: newPrecursors,
+		fuel:       newFuel,
+		phi:        phi,
+		valences:   map[string]float64{},
+		reacOpts:   reacOpt,
+	}, nil
+}
+
+func (c *Combustion) SetValences(valences map[string]float64) {
+	c.valences = valences
+	c.coefs = nil
+	c.reaction = nil
+}
+
+func parseFormula(formula string) []chemformula.Atom {
+	f, _ := chemformula.NewChemicalFormula(formula)
+	return f.ParsedFormula()
+}
+
+func (c *Combustion) precursorMetal(precursor string) (string, float64, error) {
+	metal := ""
+	amount := 0.0
+	anion := map[string]float64{}
+	for _, atom := range parseFormula(precursor) {
+		if _, ok := propellantValences[atom.Label]; ok {
+			anion[atom.Label] += atom.Amount
+			continue
+		}
+		if metal != "" && metal != atom.Label {
+			return "", 0, fmt.Errorf("precursor %s contains more than one metal", precursor)
+		}
+		metal = atom.Label
+		amount += atom.Amount
+	}
+	if metal == "" {
+		return "", 0, fmt.Errorf("there is no metal in the precursor %s", precursor)
+	}
+
+	valence, ok := c.valences[metal]
+	if !ok {
+		water := anion["O"] - 3*anion["N"]
+		if anion["N"] == 0 || anion["C"] != 0 || water < -1e-9 || math.Abs(anion["H"]-2*water) > 1e-9 {
+			return "", 0, fmt.Errorf("can't infer the valence of %s in the non-nitrate precursor %s, it should be set with SetValences", metal, precursor)
+		}
+		valence = anion["N"] * (3*propellantValences["O"] + nitrateNitrogenValence) / -amount
+	}
+	if valence <= 0 {
+		return "", 0, fmt.Errorf("valence %v of metal %s should be > 0", valence, metal)
+	}
+	return metal, valence, nil
+}
+
+func (c *Combustion) Valences() (map[string]float64, error) {
+	valences := maps.Clone(propellantValences)
+	for _, precursor := range c.precursors {
+		metal, valence, err := c.precursorMetal(precursor)
+		if err != nil {
+			return nil, err
+		}
+		valences[metal] = valence
+	}
+	maps.Copy(valences, c.valences)
+	return valences, nil
+}
+
+func compoundValence(formula string, valences map[string]float64) (float64, error) {
+	res := 0.0
+	for _, atom := range parseFormula(formula) {
+		v, ok := valences[atom.Label]
+		if !ok {
+			return 0, fmt.Errorf("no valence for element %s of %s", atom.Label, formula)
+		}
+		res += atom.Amount * v
+	}
+	return res, nil
+}
+
+func (c *Combustion) precursorCoefs() ([]float64, error) {
+	targetAtoms := parseFormula(c.target)
+	coefs := make([]float64, len(c.precursors))
+	covered := []string{}
+	for i, precursor := range c.precursors {
+		metal, _, err := c.precursorMetal(precursor)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(covered, metal) {
+			return nil, fmt.Errorf("metal %s is present in more than one precursor", metal)
+		}
+		covered = append(covered, metal)
+		inTarget, inPrecursor := 0.0, 0.0
+		for _, atom := range targetAtoms {
+			if atom.Label == metal {
+				inTarget += atom.Amount
+			}
+		}
+		for _, atom := range parseFormula(precursor) {
+			if atom.Label == metal {
+				inPrecursor += atom.Amount
+			}
+		}
+		if inTarget == 0 {
+			return nil, fmt.Errorf("metal %s of the precursor %s is absent in the target %s", metal, precursor, c.target)
+		}
+		coefs[i] = inTarget / inPrecursor
+	}
+	for _, atom := range targetAtoms {
+		if _, ok := propellantValences[atom.Label]; !ok && !slices.Contains(covered, atom.Label) {
+			return nil, fmt.Errorf("there is no precursor for %s of the target %s", atom.Label, c.target)
+		}
+	}
+	return coefs, nil
+}
+
+func (c *Combustion) OxidizerValence() (float64, error) {
+	valences, err := c.Valences()
+	if err != nil {
+		return 0, err
+	}
+	coefs, err := c.precursorCoefs()
+	if err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for i, precursor := range c.precursors {
+		v, err := compoundValence(precursor, valences)
+		if err != nil {
+			return 0, err
+		}
+		total += coefs[i] * v
+	}
+	return utils.RoundFloat(total, c.reacOpts.Precision), nil
+}
+
+func (c *Combustion) FuelValence() (float64, error) {
+	valences, err := c.Valences()
+	if err != nil {
+		return 0, err
+	}
+	v, err := compoundValence(c.fuel, valences)
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(v, c.reacOpts.Precision), nil
+}
+
+func (c *Combustion) fuelMoles() (float64, error) {
+	oxidizer, err := c.OxidizerValence()
+	if err != nil {
+		return 0, err
+	}
+	fuel, err := c.FuelValence()
+	if err != nil {
+		return 0, err
+	}
+	if fuel <= 0 || oxidizer >= 0 {
+		return 0, fmt.Errorf("fuel valence %v should be > 0 and oxidizer valence %v should be < 0", fuel, oxidizer)
+	}
+	return -c.phi * oxidizer / fuel, nil
+}
+
+func (c *Combustion) FuelMoles() (float64, error) {
+	moles, err := c.fuelMoles()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(moles, c.reacOpts.Precision), nil
+}
+
+func (c *Combustion) coefficients() ([]float64, error) {
+	if c.coefs == nil {
+		coefs, err := c.precursorCoefs()
+		if err != nil {
+			return nil, err
+		}
+		fuel, err := c.fuelMoles()
+		if err != nil {
+			return nil, err
+		}
+		coefs = append(coefs, fuel)
+
+		balance := map[string]float64{}
+		for i, formula := range append(append([]string{}, c.precursors...), c.fuel) {
+			for _, atom := range parseFormula(formula) {
+				balance[atom.Label] += coefs[i] * atom.Amount
+			}
+		}
+		for _, atom := range parseFormula(c.target) {
+			balance[atom.Label] -= atom.Amount
+		}
+		co2 := balance["C"]
+		h2o := balance["H"] / 2
+		n2 := balance["N"] / 2
+		o2 := (balance["O"] - 2*co2 - h2o) / 2
+		coefs = append(coefs, 1, co2, h2o, n2, o2)
+		for i := range coefs {
+			if math.Abs(coefs[i]) < c.reacOpts.Tolerance {
+				coefs[i] = 0
+			}
+		}
+		coefs = utils.RoundFloatS(coefs, c.reacOpts.Precision)
+		c.coefs = &coefs
+	}
+	return *c.coefs, nil
+}
+
+func (c *Combustion) Reaction() (*ChemicalReaction, error) {
+	if c.reaction == nil {
+		coefs, err := c.coefficients()
+		if err != nil {
+			return nil, err
+		}
+		n := len(c.precursors) + 1
+		reactants := []string{}
+		products := []string{}
+		add := func(side *[]string, coef float64, formula string) {
+			if coef == 1 {
+				*side = append(*side, formula)
+			} else {
+				*side = append(*side, formatFloat(coef)+formula)
+			}
+		}
+		for i, formula := range append(append([]string{}, c.precursors...), c.fuel) {
+			add(&reactants, coefs[i], formula)
+		}
+		add(&products, coefs[n], c.target)
+		for i, formula := range []string{"CO2", "H2O", "N2"} {
+			if coefs[n+1+i] < 0 {
+				return nil, fmt.Errorf("negative amount %v of %s in the products", coefs[n+1+i], formula)
+			}
+			if coefs[n+1+i] > 0 {
+				add(&products, coefs[n+1+i], formula)
+			}
+		}
+		o2 := coefs[len(coefs)-1]
+		switch {
+		case o2 < 0:
+			add(&reactants, -o2, "O2")
+		case o2 > 0:
+			add(&products, o2, "O2")
+		}
+
+		reaction := strings.Join(reactants, reactionRegexes.reactantSeparator) + "=" +
+			strings.Join(products, reactionRegexes.reactantSeparator)
+		reac, err := NewChemicalReaction(reaction, c.reacOpts)
+		if err != nil {
+			return nil, err
+		}
+		c.reaction = reac
+	}
+	return c.reaction, nil
+}
+
+func (c *Combustion) Output(printPrecision ...uint) (cbOutput, error) {
+	oxidizer, err := c.OxidizerValence()
+	if err != nil {
+		return cbOutput{}, err
+	}
+	fuel, err := c.FuelValence()
+	if err != nil {
+		return cbOutput{}, err
+	}
+	fuelMoles, err := c.FuelMoles()
+	if err != nil {
+		return cbOutput{}, err
+	}
+	valences, err := c.Valences()
+	if err != nil {
+		return cbOutput{}, err
+	}
+	reac, err := c.Reaction()
+	if err != nil {
+		return cbOutput{}, err
+	}
+	out, err := reac.Output(printPrecision...)
+	if err != nil {
+		return cbOutput{}, err
+	}
+
+	elements := slices.Sorted(maps.Keys(valences))
+	vals := make([]chemformula.Atom, len(elements))
+	for i, el := range elements {
+		vals[i] = chemformula.Atom{Label: el, Amount: valences[el]}
+	}
+	return cbOutput{
+		Phi:             c.phi,
+		Valences:        vals,
+		OxidizerValence: oxidizer,
+		FuelValence:     fuel,
+		FuelMoles:       fuelMoles,
+		Reaction:        out,
+	}, nil
+}
+
+type cbOutput struct {
+	Phi             float64
+	Valences        []chemformula.Atom
+	OxidizerValence float64
+	FuelValence     float64
+	FuelMoles       float64
+	Reaction        crOutput
+}
+
+func (o cbOutput) String() string {
+	return fmt.Sprintln("equivalence ratio:", o.Phi) +
+		fmt.Sprintln("valences:", o.Valences) +
+		fmt.Sprintln("oxidizer valence:", o.OxidizerValence) +
+		fmt.Sprintln("fuel valence:", o.FuelValence) +
+		fmt.Sprintln("fuel moles:", o.FuelMoles) +
+		o.Reaction.String()
+}
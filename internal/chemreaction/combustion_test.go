@@ -0,0 +1,153 @@
+package chemreaction
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCombustion(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		precursors []string
+		fuel       string
+		phi        float64
+		oxidizer   float64
+		fuelMoles  float64
+		expected   string
+	}{
+		{
+			name:       "ZnFe2O4 glycine",
+			target:     "ZnFe2O4",
+			precursors: []string{"Zn(NO3)2*6H2O", "Fe(NO3)3*9H2O"},
+			fuel:       "C2H5NO2",
+			phi:        1,
+			oxidizer:   -40,
+			fuelMoles:  4.44444444,
+			expected:   "Zn(NO3)2*6H2O+2Fe(NO3)3*9H2O+4.44444444C2H5NO2=ZnFe2O4+8.88888889CO2+35.11111111H2O+6.22222222N2",
+		},
+		{
+			name:       "Co3O4 urea fuel-rich",
+			target:     "Co3O4",
+			precursors: []string{"Co(NO3)2*6H2O"},
+			fuel:       "CH4N2O",
+			phi:        1.2,
+			oxidizer:   -30,
+			fuelMoles:  6,
+			expected:   "3Co(NO3)2*6H2O+6CH4N2O+2O2=Co3O4+6CO2+30H2O+9N2",
+		},
+		{
+			name:       "NiO citric acid fuel-lean",
+			target:     "NiO",
+			precursors: []string{"Ni(NO3)2*6H2O"},
+			fuel:       "C6H8O7",
+			phi:        0.5,
+			oxidizer:   -10,
+			fuelMoles:  0.27777778,
+			expected:   "Ni(NO3)2*6H2O+0.27777778C6H8O7=NiO+1.66666667CO2+7.11111111H2O+N2+1.25O2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCombustion(tt.target, tt.precursors, tt.fuel, tt.phi)
+			if err != nil {
+				t.Fatal(err)
+			}
+			oxidizer, err := c.OxidizerValence()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if oxidizer != tt.oxidizer {
+				t.Errorf("OxidizerValence() = %v, expected %v", oxidizer, tt.oxidizer)
+			}
+			fuel, _ := c.FuelMoles()
+			if math.Abs(fuel-tt.fuelMoles) > 1e-8 {
+				t.Errorf("FuelMoles() = %v, expected %v", fuel, tt.fuelMoles)
+			}
+			reac, err := c.Reaction()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := reac.FinalReaction()
+			if got != tt.expected {
+				t.Errorf("FinalReaction() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCombustion_SetValences(t *testing.T) {
+	c, _ := NewCombustion("Fe2O3", []string{"Fe(NO3)3*9H2O"}, "C2H5NO2", 1)
+	valences, err := c.Valences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valences["Fe"] != 3 {
+		t.Errorf("Valences()[Fe] = %v, expected 3", valences["Fe"])
+	}
+	c.SetValences(map[string]float64{"Fe": 2})
+	oxidizer, _ := c.OxidizerValence()
+	if oxidizer != -32 {
+		t.Errorf("OxidizerValence() = %v, expected -32", oxidizer)
+	}
+}
+
+func TestCombustion_nonNitrateValences(t *testing.T) {
+	tests := []struct {
+		target    string
+		precursor string
+		metal     string
+		valence   float64
+	}{
+		{"ZnO", "Zn(C2H3O2)2", "Zn", 2},
+		{"V2O5", "NH4VO3", "V", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.precursor, func(t *testing.T) {
+			c, _ := NewCombustion(tt.target, []string{tt.precursor}, "CH4N2O", 1)
+			_, err := c.Valences()
+			if err == nil {
+				t.Fatalf("expected error for %s without SetValences", tt.precursor)
+			}
+			c.SetValences(map[string]float64{tt.metal: tt.valence})
+			valences, err := c.Valences()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if valences[tt.metal] != tt.valence {
+				t.Errorf("Valences()[%s] = %v, expected %v", tt.metal, valences[tt.metal], tt.valence)
+			}
+			c.SetValences(map[string]float64{tt.metal: 0})
+			_, err = c.Valences()
+			if err == nil {
+				t.Errorf("expected error for zero valence")
+			}
+		})
+	}
+}
+
+func TestNewCombustion_invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		precursors []string
+		fuel       string
+		phi        float64
+	}{
+		{"zero phi", "NiO", []string{"Ni(NO3)2"}, "CH4N2O", 0},
+		{"missing precursor", "NiFe2O4", []string{"Ni(NO3)2"}, "CH4N2O", 1},
+		{"no metal", "NiO", []string{"NH4NO3"}, "CH4N2O", 1},
+		{"two metals in precursor", "NiFe2O4", []string{"NiFe2(NO3)8"}, "CH4N2O", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCombustion(tt.target, tt.precursors, tt.fuel, tt.phi)
+			if err == nil {
+				_, err = c.Reaction()
+			}
+			if err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package chemreaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+type precursorReaction struct {
+	reaction *ChemicalReaction
+	final    string
+	masses   []float64
+	gas      float64
+}
+
+func newPrecursorReaction(target string, precursors []string, options ReacOptions) (precursorReaction, error) {
+	reaction := strings.Join(precursors, reactionRegexes.reactantSeparator) + "=" + target
+	completion, err := CompleteReaction(reaction, nil, options)
+	if err != nil {
+		return precursorReaction{}, err
+	}
+	reac := completion.Reaction
+	masses, err := reac.Masses()
+	if err != nil {
+		return precursorReaction{}, err
+	}
+	final, err := reac.FinalReaction()
+	if err != nil {
+		return precursorReaction{}, err
+	}
+	gas := 0.0
+	for i := reac.decomposer.separatorPos + 1; i < len(masses); i++ {
+		gas += masses[i]
+	}
+	return precursorReaction{
+		reaction: reac,
+		final:    final,
+		masses:   masses,
+		gas:      utils.RoundFloat(gas, options.Precision),
+	}, nil
+}
+
+func (p precursorReaction) cost(precursors []string, prices map[string]float64) (float64, []string) {
+	cost := 0.0
+	missing := []string{}
+	for i, compound := range p.reaction.decomposer.reactants {
+		price, ok := prices[compound]
+		switch {
+		case ok:
+			cost += price * p.masses[i]
+		case slices.Contains(precursors, compound):
+			missing = append(missing, compound)
+		}
+	}
+	return utils.RoundFloat(cost, p.reaction.reacOpts.Precision), missing
+}
+
+type ComparisonRow struct {
+	Precursors    []string
+	Reaction      string
+	Masses        []float64
+	BatchMass     float64
+	Gas           float64
+	WeightLoss    float64
+	Cost          float64
+	MissingPrices []string
+	Error         string
+	reaction      *ChemicalReaction
+}
+
+func (r ComparisonRow) ChemicalReaction() *ChemicalReaction {
+	return r.reaction
+}
+
+type PrecursorComparison struct {
+	target   string
+	sets     [][]string
+	reacOpts ReacOptions
+	prices   map[string]float64
+	rows     *[]ComparisonRow
+}
+
+func NewPrecursorComparison(target string, precursorSets [][]string, options ...ReacOptions) (*PrecursorComparison, error) {
+	newTarget := strings.Replace(target, " ", "", -1)
+	_, err := chemformula.NewChemicalFormula(newTarget)
+	if err != nil {
+		return nil, err
+	}
+	if len(precursorSets) == 0 {
+		return nil, fmt.Errorf("no precursor sets to compare")
+	}
+	sets := make([][]string, len(precursorSets))
+	for i, set := range precursorSets {
+		if len(set) == 0 {
+			return nil, fmt.Errorf("precursor set %d is empty", i+1)
+		}
+		for _, precursor := range set {
+			precursor = strings.Replace(precursor, " ", "", -1)
+			_, err := chemformula.NewChemicalFormula(precursor)
+			if err != nil {
+				return nil, err
+			}
+			if slices.Contains(sets[i], precursor) {
+				return nil, fmt.Errorf("duplicate precursor %s in set %d", precursor, i+1)
+			}
+			sets[i] = append(sets[i], precursor)
+		}
+	}
+
+	var reacOpt ReacOptions
+	if options == nil {
+		reacOpt = ReacOptions{
+			Rmode:      Balance,
+			Target:     0,
+			TargerMass: 1.0,
+			Intify:     true,
+			Precision:  8,
+			Tolerance:  1e-8,
+		}
+	} else {
+		reacOpt = options[0]
+	}
+	reacOpt.Rmode = Balance
+	reacOpt.Target = 0
+
+	return &PrecursorComparison{
+		target:   newTarget,
+		sets:     sets,
+		reacOpts: reacOpt,
+	}, nil
+}
+
+func (c *PrecursorComparison) SetPrices(prices map[string]float64) error {
+	newPrices := map[string]float64{}
+	for formula, price := range prices {
+		if price < 0 {
+			return fmt.Errorf("negative price %v of %s", price, formula)
+		}
+		newPrices[strings.Replace(formula, " ", "", -1)] = price
+	}
+	c.prices = newPrices
+	c.rows = nil
+	return nil
+}
+
+func (c *PrecursorComparison) Precursors() []string {
+	precursors := []string{}
+	for _, set := range c.sets {
+		for _, precursor := range set {
+			if !slices.Contains(precursors, precursor) {
+				precursors = append(precursors, precursor)
+			}
+		}
+	}
+	return precursors
+}
+
+func (c *PrecursorComparison) row(set []string) ComparisonRow {
+	row := ComparisonRow{Precursors: set}
+	p, err := newPrecursorReaction(c.target, set, c.reacOpts)
+	if err != nil {
+		row.Error = err.Error()
+		return row
+	}
+	row.reaction = p.reaction
+	row.Reaction = p.final
+	row.Masses = p.masses[:len(set)]
+	row.BatchMass = utils.RoundFloat(utils.SumFloatS(row.Masses), c.reacOpts.Precision)
+	row.Gas = p.gas
+	if row.BatchMass > 0 {
+		row.WeightLoss = utils.RoundFloat((row.BatchMass-c.reacOpts.TargerMass)/row.BatchMass*100, c.reacOpts.Precision)
+	}
+	row.Cost, row.MissingPrices = p.cost(set, c.prices)
+	return row
+}
+
+func (c *PrecursorComparison) Rows() []ComparisonRow {
+	if c.rows == nil {
+		rows := make([]ComparisonRow, len(c.sets))
+		for i, set := range c.sets {
+			rows[i] = c.row(set)
+		}
+		c.rows = &rows
+	}
+	return *c.rows
+}
+
+func (c *PrecursorComparison) Output(printPrecision ...uint) cmpOutput {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	rows := slices.Clone(c.Rows())
+	for i, row := range rows {
+		rows[i].Masses = utils.RoundFloatS(row.Masses, pPrecision)
+		rows[i].BatchMass = utils.RoundFloat(row.BatchMass, pPrecision)
+		rows[i].Gas = utils.RoundFloat(row.Gas, pPrecision)
+		rows[i].WeightLoss = utils.RoundFloat(row.WeightLoss, pPrecision)
+		rows[i].Cost = utils.RoundFloat(row.Cost, pPrecision)
+	}
+
+	return cmpOutput{
+		Target:     c.target,
+		TargetMass: c.reacOpts.TargerMass,
+		Precursors: c.Precursors(),
+		Priced:     c.prices != nil,
+		Rows:       rows,
+	}
+}
+
+type cmpOutput struct {
+	Target     string
+	TargetMass float64
+	Precursors []string
+	Priced     bool
+	Rows       []ComparisonRow
+}
+
+func (o cmpOutput) table() table {
+	t := table{header: []string{"precursors"}}
+	for _, precursor := range o.Precursors {
+		t.header = append(t.header, "m("+precursor+"), g")
+	}
+	t.header = append(t.header, "batch, g", "gas, g", "weight loss, %")
+	if o.Priced {
+		t.header = append(t.header, "cost")
+	}
+	t.header = append(t.header, "reaction")
+
+	for _, row := range o.Rows {
+		line := []string{strings.Join(row.Precursors, ", ")}
+		for _, precursor := range o.Precursors {
+			i := slices.Index(row.Precursors, precursor)
+			if i < 0 || row.Error != "" {
+				line = append(line, "-")
+			} else {
+				line = append(line, formatFloat(row.Masses[i]))
+			}
+		}
+		if row.Error != "" {
+			line = append(line, "-", "-", "-")
+			if o.Priced {
+				line = append(line, "-")
+			}
+			line = append(line, "error: "+row.Error)
+			t.rows = append(t.rows, line)
+			continue
+		}
+		line = append(line, formatFloat(row.BatchMass), formatFloat(row.Gas), formatFloat(row.WeightLoss))
+		if o.Priced {
+			if len(row.MissingPrices) > 0 {
+				line = append(line, "-")
+			} else {
+				line = append(line, formatFloat(row.Cost))
+			}
+		}
+		line = append(line, row.Reaction)
+		t.rows = append(t.rows, line)
+	}
+	return t
+}
+
+func (o cmpOutput) String() string {
+	return fmt.Sprintln("target:", o.Target) +
+		fmt.Sprintln("target mass:", o.TargetMass) +
+		o.table().String()
+}
+
+func (o cmpOutput) CSV() (string, error) {
+	return o.table().CSV()
+}
+
+func (o cmpOutput) JSON() ([]byte, error) {
+	return json.Marshal(o)
+}
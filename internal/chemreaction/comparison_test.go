@@ -0,0 +1,98 @@
+package chemreaction
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+func TestPrecursorComparison(t *testing.T) {
+	c, err := NewPrecursorComparison("BaTiO3", [][]string{{"BaCO3", "TiO2"}, {"Ba(NO3)2", "TiO2"}, {"BaO", "TiO2"}, {"BaCl2", "TiO2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.SetPrices(map[string]float64{"BaCO3": 0.1, "TiO2": 0.05, "BaO": 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		reaction   string
+		batch      float64
+		gas        float64
+		weightLoss float64
+		cost       float64
+		missing    int
+	}{
+		{"BaCO3+TiO2=BaTiO3+CO2", 1.18872, 0.18872, 15.8763, 0.10175, 0},
+		{"2Ba(NO3)2+2TiO2=2BaTiO3+O2+4NO2", 1.46318, 0.46318, 31.6556, 0.01712, 1},
+		{"BaO+TiO2=BaTiO3", 1, 0, 0, 0.34588, 0},
+	}
+	rows := c.Rows()
+	for i, tt := range tests {
+		row := rows[i]
+		if row.Error != "" {
+			t.Fatal(row.Error)
+		}
+		if row.Reaction != tt.reaction || !row.ChemicalReaction().IsBalanced() {
+			t.Errorf("row %d: reaction %s, expected %s", i, row.Reaction, tt.reaction)
+		}
+		if math.Abs(row.BatchMass-tt.batch) > 1e-4 || math.Abs(row.Gas-tt.gas) > 1e-4 ||
+			math.Abs(row.WeightLoss-tt.weightLoss) > 1e-3 || math.Abs(row.Cost-tt.cost) > 1e-4 ||
+			len(row.MissingPrices) != tt.missing {
+			t.Errorf("row %d: %+v", i, row)
+		}
+		if math.Abs(utils.SumFloatS(row.Masses)-row.BatchMass) > 1e-9 {
+			t.Errorf("row %d: masses %v don't sum up to %v", i, row.Masses, row.BatchMass)
+		}
+	}
+	if rows[3].Error == "" {
+		t.Error("expected an error for BaCl2")
+	}
+
+	out := c.Output()
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 7 || !strings.HasPrefix(lines[2], "precursors  ") || !strings.Contains(lines[2], "cost") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	csv, err := out.CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(csv, "\"BaO, TiO2\",-,0.3425,-,0.6575,-,1,0,0,0.3459,BaO+TiO2=BaTiO3\n") {
+		t.Errorf("unexpected CSV:\n%s", csv)
+	}
+	data, err := out.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct{ Rows []ComparisonRow }
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Rows) != 4 {
+		t.Errorf("expected 4 rows in JSON, got %d", len(decoded.Rows))
+	}
+}
+
+func TestPrecursorComparisonErrors(t *testing.T) {
+	tests := []struct {
+		target string
+		sets   [][]string
+	}{
+		{"BaTiO3", nil},
+		{"BaTiO3", [][]string{{}}},
+		{"BaTiO3", [][]string{{"BaO", "BaO"}}},
+		{"Xx", [][]string{{"BaO"}}},
+	}
+	for _, tt := range tests {
+		_, err := NewPrecursorComparison(tt.target, tt.sets)
+		if err == nil {
+			t.Errorf("expected an error for %s %v", tt.target, tt.sets)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package chemreaction
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+	"gonum.org/v1/gonum/mat"
+)
+
+const maxSpectators = 3
+
+var spectatorSpecies = []string{"O2", "CO2", "H2O", "NH3", "NO2", "N2", "H2"}
+
+type Completion struct {
+	Reaction  *ChemicalReaction
+	Reactants []string
+	Products  []string
+}
+
+type spectator struct {
+	formula string
+	parsed  []chemformula.Atom
+	product bool
+}
+
+func positiveSolution(parsed [][]chemformula.Atom, separatorPos int) ([]float64, bool) {
+	matrix := createReacMatrix(parsed)
+	rows, cols := matrix.Dims()
+	for i := range rows {
+		for j := separatorPos; j < cols; j++ {
+			matrix.Set(i, j, -matrix.At(i, j))
+		}
+	}
+	b := make([]float64, rows)
+	for i := range rows {
+		b[i] = -utils.SumFloatS(mat.Row(nil, i, matrix))
+	}
+	y, residual, err := utils.NNLS(matrix, b, 1e-12)
+	if err != nil || residual > 1e-9*math.Max(1, mat.Norm(mat.NewVecDense(rows, b), 2)) {
+		return nil, false
+	}
+	x := make([]float64, cols)
+	for i := range x {
+		x[i] = 1 + y[i]
+	}
+	return x, true
+}
+
+func combinations(n int, size int) [][]int {
+	if size == 0 {
+		return [][]int{{}}
+	}
+	sets := [][]int{}
+	for _, set := range combinations(n, size-1) {
+		start := 0
+		if len(set) > 0 {
+			start = set[len(set)-1] + 1
+		}
+		for i := start; i < n; i++ {
+			sets = append(sets, append(append([]int{}, set...), i))
+		}
+	}
+	return sets
+}
+
+func CompleteReaction(reaction string, spectators []string, options ...ReacOptions) (Completion, error) {
+	newReaction := strings.Replace(reaction, " ", "", -1)
+	if extractSeparator(newReaction) == "" {
+		return Completion{}, fmt.Errorf("no separator between reactants and products: %s in the reaction '%s'",
+			reactionRegexes.reactionSeparators, newReaction)
+	}
+	decomp, err := newReactionDecomposer(newReaction)
+	if err != nil {
+		return Completion{}, err
+	}
+	parsed := make([][]chemformula.Atom, len(decomp.compounds))
+	for i, compound := range decomp.compounds {
+		f, err := chemformula.NewChemicalFormula(compound)
+		if err != nil {
+			return Completion{}, err
+		}
+		parsed[i] = f.ParsedFormula()
+	}
+
+	var reacOpt ReacOptions
+	if options == nil {
+		reacOpt = ReacOptions{
+			Rmode:      Balance,
+			Target:     0,
+			TargerMass: 1.0,
+			Intify:     true,
+			Precision:  8,
+			Tolerance:  1e-8,
+		}
+	} else {
+		reacOpt = options[0]
+	}
+	reacOpt.Rmode = Balance
+
+	if spectators == nil {
+		spectators = spectatorSpecies
+	}
+	candidates := []spectator{}
+	for _, formula := range spectators {
+		formula = strings.Replace(formula, " ", "", -1)
+		if slices.Contains(decomp.compounds, formula) {
+			continue
+		}
+		f, err := chemformula.NewChemicalFormula(formula)
+		if err != nil {
+			return Completion{}, err
+		}
+		for _, product := range []bool{true, false} {
+			candidates = append(candidates, spectator{formula: formula, parsed: f.ParsedFormula(), product: product})
+		}
+	}
+
+	for size := 0; size <= min(maxSpectators, len(candidates)); size++ {
+	sets:
+		for _, set := range combinations(len(candidates), size) {
+			completion := Completion{Reactants: []string{}, Products: []string{}}
+			reacParsed := append([][]chemformula.Atom{}, parsed[:decomp.separatorPos]...)
+			prodParsed := append([][]chemformula.Atom{}, parsed[decomp.separatorPos:]...)
+			for _, i := range set {
+				c := candidates[i]
+				if slices.Contains(completion.Reactants, c.formula) || slices.Contains(completion.Products, c.formula) {
+					continue sets
+				}
+				if c.product {
+					completion.Products = append(completion.Products, c.formula)
+					prodParsed = append(prodParsed, c.parsed)
+				} else {
+					completion.Reactants = append(completion.Reactants, c.formula)
+					reacParsed = append(reacParsed, c.parsed)
+				}
+			}
+			coefs, ok := positiveSolution(append(reacParsed, prodParsed...), len(reacParsed))
+			if !ok {
+				continue
+			}
+
+			completed := strings.Join(append(append([]string{}, decomp.reactants...), completion.Reactants...), reactionRegexes.reactantSeparator) +
+				decomp.separator +
+				strings.Join(append(append([]string{}, decomp.products...), completion.Products...), reactionRegexes.reactantSeparator)
+			reac, err := NewChemicalReaction(completed, reacOpt)
+			if err != nil {
+				return Completion{}, err
+			}
+			_, err = reac.Coefficients()
+			if err != nil {
+				if reacOpt.Intify {
+					coefs, err = intifyVector(coefs, 1_000_000)
+					if err != nil {
+						return Completion{}, err
+					}
+				}
+				err = reac.SetCoefficients(utils.RoundFloatS(coefs, reacOpt.Precision))
+				if err != nil {
+					return Completion{}, err
+				}
+			}
+			completion.Reaction = reac
+			return completion, nil
+		}
+	}
+	return Completion{}, fmt.Errorf("can't complete the reaction %s with up to %d of %v", newReaction, maxSpectators, spectators)
+}
@@ -0,0 +1,102 @@
+package chemreaction
+
+import (
+	"bufio"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompleteReaction(t *testing.T) {
+	tests := []struct {
+		reaction  string
+		reactants []string
+		products  []string
+		final     string
+	}{
+		{"BaCO3+TiO2=BaTiO3", []string{}, []string{"CO2"}, "BaCO3+TiO2=BaTiO3+CO2"},
+		{"Gd2O3+NH4VO3=GdVO4", []string{}, []string{"H2O", "NH3"}, "Gd2O3+2NH4VO3=2GdVO4+H2O+2NH3"},
+		{"Fe=Fe2O3", []string{"O2"}, []string{}, "4Fe+3O2=2Fe2O3"},
+		{"Cu(NO3)2=CuO", []string{}, []string{"O2", "NO2"}, "2Cu(NO3)2=2CuO+O2+4NO2"},
+		{"BaCO3+Co3O4=BaCoO3", []string{"O2"}, []string{"CO2"}, "3BaCO3+Co3O4+O2=3BaCoO3+3CO2"},
+		{"H2+O2=H2O", []string{}, []string{}, "2H2+O2=2H2O"},
+		{"Ti+O2=TiO+Ti2O3+Ti3O5+TiO2", []string{}, []string{}, "14Ti+11O2=2TiO+2Ti2O3+2Ti3O5+2TiO2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reaction, func(t *testing.T) {
+			completion, err := CompleteReaction(tt.reaction, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(completion.Reactants, tt.reactants) || !slices.Equal(completion.Products, tt.products) {
+				t.Errorf("CompleteReaction() added %v and %v, expected %v and %v",
+					completion.Reactants, completion.Products, tt.reactants, tt.products)
+			}
+			final, err := completion.Reaction.FinalReaction()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if final != tt.final {
+				t.Errorf("FinalReaction() = %s, expected %s", final, tt.final)
+			}
+		})
+	}
+}
+
+func TestCompleteReactionErrors(t *testing.T) {
+	tests := []struct {
+		reaction   string
+		spectators []string
+	}{
+		{"Fe=Cu", nil},
+		{"BaCO3+TiO2=BaTiO3", []string{"O2", "H2O"}},
+		{"BaCO3+TiO2", nil},
+		{"BaCO3+Xx=BaTiO3", nil},
+	}
+	for _, tt := range tests {
+		_, err := CompleteReaction(tt.reaction, tt.spectators)
+		if err == nil {
+			t.Errorf("expected an error for %s", tt.reaction)
+		}
+	}
+}
+
+func TestCompleteTextMined(t *testing.T) {
+	file, err := os.Open("../../data/text_mined_reactions.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	checked := 0
+	for scanner.Scan() && checked < 100 {
+		decomp, err := newReactionDecomposer(scanner.Text())
+		if err != nil {
+			t.Fatal(err)
+		}
+		products := []string{}
+		for _, product := range decomp.products {
+			if !slices.Contains(spectatorSpecies, product) {
+				products = append(products, product)
+			}
+		}
+		if len(products) == len(decomp.products) {
+			continue
+		}
+		reaction := strings.Join(decomp.reactants, "+") + "=" + strings.Join(products, "+")
+		completion, err := CompleteReaction(reaction, nil)
+		if err != nil {
+			t.Errorf("CompleteReaction(%s) failed: %v", reaction, err)
+			continue
+		}
+		if !completion.Reaction.IsBalanced() {
+			t.Errorf("completed reaction of %s is not balanced", reaction)
+		}
+		checked++
+	}
+	if checked == 0 {
+		t.Error("no reactions with gaseous by-products were checked")
+	}
+}
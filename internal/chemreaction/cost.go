@@ -0,0 +1,321 @@
+package chemreaction
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+var priceUnits = map[string]float64{"g": 1, "kg": 1000}
+
+type PriceEntry struct {
+	Formula  string  `json:"formula"`
+	Grade    string  `json:"grade"`
+	Price    float64 `json:"price"`
+	Unit     string  `json:"unit"`
+	PackSize float64 `json:"pack"`
+	Currency string  `json:"currency"`
+}
+
+func (e PriceEntry) perGram() float64 {
+	return e.Price / priceUnits[e.Unit]
+}
+
+type PriceCatalogue struct {
+	entries  []PriceEntry
+	keys     []string
+	currency string
+}
+
+func compositionKey(atoms []chemformula.Atom) string {
+	amounts := map[string]float64{}
+	for _, atom := range atoms {
+		amounts[atom.Label] += atom.Amount
+	}
+	labels := slices.Sorted(maps.Keys(amounts))
+	var key strings.Builder
+	for _, label := range labels {
+		key.WriteString(label + formatFloat(utils.RoundFloat(amounts[label], 8)))
+	}
+	return key.String()
+}
+
+func NewPriceCatalogue(entries []PriceEntry) (*PriceCatalogue, error) {
+	catalogue := &PriceCatalogue{}
+	for _, entry := range entries {
+		err := catalogue.Add(entry)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return catalogue, nil
+}
+
+func ReadPriceJSON(r io.Reader) (*PriceCatalogue, error) {
+	var entries []PriceEntry
+	err := json.NewDecoder(r).Decode(&entries)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode price catalogue: %w", err)
+	}
+	return NewPriceCatalogue(entries)
+}
+
+func ReadPriceCSV(r io.Reader) (*PriceCatalogue, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("can't read price catalogue: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty price catalogue")
+	}
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"formula", "price"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("there is no column '%s' in price catalogue", name)
+		}
+	}
+	field := func(record []string, name string) string {
+		if col, ok := columns[name]; ok {
+			return strings.TrimSpace(record[col])
+		}
+		return ""
+	}
+
+	entries := []PriceEntry{}
+	for line, record := range records[1:] {
+		entry := PriceEntry{
+			Formula:  field(record, "formula"),
+			Grade:    field(record, "grade"),
+			Unit:     field(record, "unit"),
+			Currency: field(record, "currency"),
+		}
+		entry.Price, err = strconv.ParseFloat(field(record, "price"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price in line %d: %w", line+2, err)
+		}
+		if pack := field(record, "pack"); pack != "" {
+			entry.PackSize, err = strconv.ParseFloat(pack, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pack size in line %d: %w", line+2, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return NewPriceCatalogue(entries)
+}
+
+func LoadPriceCatalogue(path string) (*PriceCatalogue, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReadPriceJSON(file)
+	case ".csv":
+		return ReadPriceCSV(file)
+	default:
+		return nil, fmt.Errorf("unknown price catalogue format of %s", path)
+	}
+}
+
+func (c *PriceCatalogue) Add(entry PriceEntry) error {
+	f, err := chemformula.NewChemicalFormula(entry.Formula)
+	if err != nil {
+		return err
+	}
+	entry.Formula = strings.Replace(entry.Formula, " ", "", -1)
+	key := compositionKey(f.ParsedFormula())
+	if entry.Unit == "" {
+		entry.Unit = "g"
+	}
+	if _, ok := priceUnits[entry.Unit]; !ok {
+		return fmt.Errorf("unknown price unit '%s' of %s, expected g or kg", entry.Unit, entry.Formula)
+	}
+	if entry.Price < 0 || entry.PackSize < 0 {
+		return fmt.Errorf("negative price or pack size of %s", entry.Formula)
+	}
+	if entry.Currency != "" {
+		if c.currency != "" && c.currency != entry.Currency {
+			return fmt.Errorf("currency %s of %s differs from %s of the catalogue", entry.Currency, entry.Formula, c.currency)
+		}
+		c.currency = entry.Currency
+	}
+	for i, e := range c.entries {
+		if c.keys[i] == key && e.Grade == entry.Grade && e.PackSize == entry.PackSize {
+			return fmt.Errorf("duplicate price of %s (%s, %v g)", entry.Formula, entry.Grade, entry.PackSize)
+		}
+	}
+	c.entries = append(c.entries, entry)
+	c.keys = append(c.keys, key)
+	return nil
+}
+
+func (c *PriceCatalogue) Entries() []PriceEntry {
+	return c.entries
+}
+
+func (c *PriceCatalogue) Currency() string {
+	return c.currency
+}
+
+func packCost(entry PriceEntry, mass float64) (int, float64) {
+	if entry.PackSize == 0 {
+		return 0, entry.perGram() * mass
+	}
+	packs := int(math.Ceil(mass/entry.PackSize - 1e-9))
+	return packs, float64(packs) * entry.PackSize * entry.perGram()
+}
+
+func (c *PriceCatalogue) Lookup(formula string, grade string, mass float64) (PriceEntry, error) {
+	formula = strings.Replace(formula, " ", "", -1)
+	f, err := chemformula.NewChemicalFormula(formula)
+	if err != nil {
+		return PriceEntry{}, err
+	}
+	key := compositionKey(f.ParsedFormula())
+	found := false
+	var best PriceEntry
+	bestCost := 0.0
+	for i, e := range c.entries {
+		if c.keys[i] != key || (grade != "" && e.Grade != grade) {
+			continue
+		}
+		_, cost := packCost(e, mass)
+		if !found || cost < bestCost {
+			best, bestCost, found = e, cost, true
+		}
+	}
+	if !found {
+		if grade == "" {
+			return PriceEntry{}, fmt.Errorf("there is no price of %s", formula)
+		}
+		return PriceEntry{}, fmt.Errorf("there is no price of %s (%s)", formula, grade)
+	}
+	return best, nil
+}
+
+type CompoundCost struct {
+	Formula  string
+	Grade    string
+	Mass     float64
+	Cost     float64
+	Packs    int
+	PackSize float64
+	PackCost float64
+}
+
+type ReactionCost struct {
+	Currency    string
+	TargetMass  float64
+	Compounds   []CompoundCost
+	Missing     []string
+	BatchCost   float64
+	PackCost    float64
+	CostPerGram float64
+	CostPerMole float64
+}
+
+func (r *ChemicalReaction) Cost(catalogue *PriceCatalogue, grades map[string]string) (ReactionCost, error) {
+	if catalogue == nil {
+		return ReactionCost{}, fmt.Errorf("no price catalogue")
+	}
+	masses, err := r.Masses()
+	if err != nil {
+		return ReactionCost{}, err
+	}
+	molars, err := r.MolarMasses()
+	if err != nil {
+		return ReactionCost{}, err
+	}
+	target, err := r.calculatedTarget()
+	if err != nil {
+		return ReactionCost{}, err
+	}
+	prec := r.reacOpts.Precision
+
+	cost := ReactionCost{
+		Currency:   catalogue.currency,
+		TargetMass: r.reacOpts.TargerMass,
+		Compounds:  []CompoundCost{},
+		Missing:    []string{},
+	}
+	for i, compound := range r.decomposer.reactants {
+		grade, ok := grades[compound]
+		entry, err := catalogue.Lookup(compound, grade, masses[i])
+		if err != nil {
+			if ok {
+				return ReactionCost{}, err
+			}
+			cost.Missing = append(cost.Missing, compound)
+			continue
+		}
+		packs, pack := packCost(entry, masses[i])
+		used := entry.perGram() * masses[i]
+		cost.Compounds = append(cost.Compounds, CompoundCost{
+			Formula:  compound,
+			Grade:    entry.Grade,
+			Mass:     masses[i],
+			Cost:     utils.RoundFloat(used, prec),
+			Packs:    packs,
+			PackSize: entry.PackSize,
+			PackCost: utils.RoundFloat(pack, prec),
+		})
+		cost.BatchCost += used
+		cost.PackCost += pack
+	}
+	if len(cost.Missing) > 0 {
+		cost.BatchCost = 0
+		cost.PackCost = 0
+		return cost, nil
+	}
+	if r.reacOpts.TargerMass > 0 {
+		cost.CostPerGram = utils.RoundFloat(cost.BatchCost/r.reacOpts.TargerMass, prec)
+		cost.CostPerMole = utils.RoundFloat(cost.BatchCost/r.reacOpts.TargerMass*molars[target], prec)
+	}
+	cost.BatchCost = utils.RoundFloat(cost.BatchCost, prec)
+	cost.PackCost = utils.RoundFloat(cost.PackCost, prec)
+	return cost, nil
+}
+
+func (c ReactionCost) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	for _, compound := range c.Compounds {
+		fmt.Fprintf(w, "%s\t%s\tm = %v\tg\tcost = %v\t%s", compound.Formula, compound.Grade, compound.Mass, compound.Cost, c.Currency)
+		if compound.PackSize > 0 {
+			fmt.Fprintf(w, "\t%d x %v g\t%v\t%s", compound.Packs, compound.PackSize, compound.PackCost, c.Currency)
+		}
+		fmt.Fprintln(w)
+	}
+
+	w.Flush()
+	out := buf.String()
+	if len(c.Missing) > 0 {
+		return out + fmt.Sprint("no price: ", c.Missing, ", the total cost is unknown")
+	}
+	return out +
+		fmt.Sprintf("batch cost for %v g of target: %v %s\n", c.TargetMass, c.BatchCost, c.Currency) +
+		fmt.Sprintf("cost of packs: %v %s\n", c.PackCost, c.Currency) +
+		fmt.Sprintf("cost per gram of product: %v %s\n", c.CostPerGram, c.Currency) +
+		fmt.Sprintf("cost per mole of target: %v %s", c.CostPerMole, c.Currency)
+}
@@ -0,0 +1,161 @@
+package chemreaction
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testPriceJSON = `[
+	{"formula": "BaCO3", "grade": "pure", "price": 0.5, "pack": 100, "currency": "EUR"},
+	{"formula": "BaCO3", "grade": "99.99", "price": 1.5, "pack": 25, "currency": "EUR"},
+	{"formula": "TiO2", "grade": "pure", "price": 100, "unit": "kg"}
+]`
+
+const testPriceCSV = `formula,grade,price,unit,pack,currency
+BaCO3,pure,0.5,g,100,EUR
+BaCO3,99.99,1.5,g,25,EUR
+TiO2,pure,100,kg,,
+`
+
+func TestReadPrice(t *testing.T) {
+	fromJSON, err := ReadPriceJSON(strings.NewReader(testPriceJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromCSV, err := ReadPriceCSV(strings.NewReader(testPriceCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(fromJSON.Entries(), fromCSV.Entries()) {
+		t.Errorf("JSON %v != CSV %v", fromJSON.Entries(), fromCSV.Entries())
+	}
+	if fromCSV.Currency() != "EUR" {
+		t.Errorf("currency %s, expected EUR", fromCSV.Currency())
+	}
+}
+
+func TestPriceCatalogueErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []PriceEntry
+	}{
+		{"unit", []PriceEntry{{Formula: "TiO2", Price: 1, Unit: "lb"}}},
+		{"negative", []PriceEntry{{Formula: "TiO2", Price: -1}}},
+		{"formula", []PriceEntry{{Formula: "TiO2)", Price: 1}}},
+		{"currency", []PriceEntry{{Formula: "TiO2", Price: 1, Currency: "EUR"}, {Formula: "BaCO3", Price: 1, Currency: "USD"}}},
+		{"duplicate", []PriceEntry{{Formula: "TiO2", Price: 1}, {Formula: "TiO2", Price: 2, Unit: "kg"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPriceCatalogue(tt.entries)
+			if err == nil {
+				t.Errorf("expected error for %v", tt.entries)
+			}
+		})
+	}
+}
+
+func TestPriceLookup(t *testing.T) {
+	catalogue, _ := ReadPriceJSON(strings.NewReader(testPriceJSON))
+	tests := []struct {
+		grade    string
+		mass     float64
+		expected string
+	}{
+		{"", 10, "99.99"},
+		{"", 50, "pure"},
+		{"pure", 10, "pure"},
+		{"99.99", 50, "99.99"},
+	}
+	for _, tt := range tests {
+		entry, err := catalogue.Lookup("BaCO3", tt.grade, tt.mass)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Grade != tt.expected {
+			t.Errorf("Lookup(%q, %v) = %s, expected %s", tt.grade, tt.mass, entry.Grade, tt.expected)
+		}
+	}
+	_, err := catalogue.Lookup("BaCO3", "ACS", 1)
+	if err == nil {
+		t.Error("expected error for unknown grade")
+	}
+}
+
+func TestReactionCost(t *testing.T) {
+	catalogue, _ := ReadPriceJSON(strings.NewReader(testPriceJSON))
+	reac, _ := NewChemicalReaction("BaCO3+TiO2=BaTiO3+CO2", ReacOptions{
+		Rmode: Balance, Target: 0, TargerMass: 40, Intify: true, Precision: 8, Tolerance: 1e-8,
+	})
+	masses, _ := reac.Masses()
+	molars, _ := reac.MolarMasses()
+
+	cost, err := reac.Cost(catalogue, map[string]string{"BaCO3": "pure"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	batch := 0.5*masses[0] + 0.1*masses[1]
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"BaCO3", cost.Compounds[0].Cost, 0.5 * masses[0]},
+		{"BaCO3 packs", float64(cost.Compounds[0].Packs), 1},
+		{"BaCO3 pack cost", cost.Compounds[0].PackCost, 50},
+		{"TiO2", cost.Compounds[1].Cost, 0.1 * masses[1]},
+		{"TiO2 pack cost", cost.Compounds[1].PackCost, 0.1 * masses[1]},
+		{"batch", cost.BatchCost, batch},
+		{"packs", cost.PackCost, 50 + 0.1*masses[1]},
+		{"per gram", cost.CostPerGram, batch / 40},
+		{"per mole", cost.CostPerMole, batch / 40 * molars[2]},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.expected) > 1e-6 {
+			t.Errorf("%s = %v, expected %v", tt.name, tt.got, tt.expected)
+		}
+	}
+	if len(cost.Missing) != 0 || cost.Currency != "EUR" {
+		t.Errorf("unexpected missing %v or currency %s", cost.Missing, cost.Currency)
+	}
+
+	_, err = reac.Cost(catalogue, map[string]string{"TiO2": "ACS"})
+	if err == nil {
+		t.Error("expected error for unknown grade")
+	}
+
+	partial, _ := NewPriceCatalogue([]PriceEntry{{Formula: "TiO2", Price: 0.1}})
+	cost, err = reac.Cost(partial, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cost.Missing, []string{"BaCO3"}) || len(cost.Compounds) != 1 {
+		t.Errorf("missing %v, compounds %v", cost.Missing, cost.Compounds)
+	}
+	if cost.BatchCost != 0 || cost.PackCost != 0 || cost.CostPerGram != 0 || cost.CostPerMole != 0 {
+		t.Errorf("totals with missing prices = %v, expected zeros", cost)
+	}
+
+	_, err = reac.Cost(nil, nil)
+	if err == nil {
+		t.Error("expected error for nil catalogue")
+	}
+}
+
+func TestReactionCostComposition(t *testing.T) {
+	catalogue, _ := ReadPriceJSON(strings.NewReader(testPriceJSON))
+	reac, _ := NewChemicalReaction("Ba(CO3)+TiO2=BaTiO3+CO2")
+	cost, err := reac.Cost(catalogue, map[string]string{"Ba(CO3)": "pure"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cost.Missing) != 0 || cost.Compounds[0].Grade != "pure" {
+		t.Errorf("Ba(CO3) should match BaCO3, got missing %v", cost.Missing)
+	}
+	_, err = NewPriceCatalogue([]PriceEntry{{Formula: "BaCO3", Price: 1}, {Formula: "Ba(CO3)", Price: 2}})
+	if err == nil {
+		t.Error("expected error for duplicate composition")
+	}
+}
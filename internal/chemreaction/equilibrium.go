@@ -0,0 +1,366 @@
+package chemreaction
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	gasConstant      = 8.314462618
+	gasPhase         = "g"
+	minBarrierWeight = 1e-14
+	maxNewtonSteps   = 200
+	maxKKTResidual   = 1e-6
+)
+
+type EquilibriumComposition struct {
+	Temperature float64
+	Pressure    float64
+	Species     []string
+	Phases      []string
+	Moles       []float64
+	Gibbs       float64
+}
+
+func (c EquilibriumComposition) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	for i, species := range c.Species {
+		fmt.Fprintf(w, "%s\t(%s)\tn = %v\tmol\n", species, c.Phases[i], c.Moles[i])
+	}
+
+	w.Flush()
+	return fmt.Sprintf("T = %v K, P = %v bar\n", c.Temperature, c.Pressure) +
+		buf.String() + fmt.Sprintf("G = %v kJ", c.Gibbs)
+}
+
+type Equilibrium struct {
+	species   []ThermoData
+	parsed    [][]chemformula.Atom
+	elements  []string
+	totals    []float64
+	precision uint
+	tolerance float64
+}
+
+func NewEquilibrium(initial *ChemicalReaction, species []string, table *ThermoTable, phases map[string]string) (*Equilibrium, error) {
+	coefs, err := initial.Coefficients()
+	if err != nil {
+		return nil, err
+	}
+	reacParsed, err := initial.ParsedFormulas()
+	if err != nil {
+		return nil, err
+	}
+	totals := map[string]float64{}
+	for i := range initial.decomposer.reactants {
+		for _, atom := range reacParsed[i] {
+			totals[atom.Label] += coefs.Result[i] * atom.Amount
+		}
+	}
+
+	data := []ThermoData{}
+	parsed := [][]chemformula.Atom{}
+	for _, formula := range species {
+		f, err := chemformula.NewChemicalFormula(formula)
+		if err != nil {
+			return nil, err
+		}
+		found := table.Phases(formula)
+		if phase, ok := phases[formula]; ok {
+			d, err := table.Lookup(formula, phase)
+			if err != nil {
+				return nil, err
+			}
+			found = []ThermoData{d}
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("there is no thermochemical data for %s", formula)
+		}
+		for _, d := range found {
+			data = append(data, d)
+			parsed = append(parsed, f.ParsedFormula())
+		}
+	}
+
+	elements := matrixElements(parsed)
+	b := make([]float64, len(elements))
+	for i, element := range elements {
+		b[i] = totals[element]
+	}
+	for element := range totals {
+		if totals[element] > 0 && !slices.Contains(elements, element) {
+			return nil, fmt.Errorf("there are no species with element %s", element)
+		}
+	}
+
+	return &Equilibrium{
+		species:   data,
+		parsed:    parsed,
+		elements:  elements,
+		totals:    b,
+		precision: initial.reacOpts.Precision,
+		tolerance: initial.reacOpts.Tolerance,
+	}, nil
+}
+
+func (e *Equilibrium) Species() []string {
+	species := make([]string, len(e.species))
+	for i, d := range e.species {
+		species[i] = fmt.Sprintf("%s (%s)", d.Formula, d.Phase)
+	}
+	return species
+}
+
+func (e *Equilibrium) Elements() []string {
+	return e.elements
+}
+
+func (e *Equilibrium) ElementTotals() []float64 {
+	return utils.RoundFloatS(e.totals, e.precision)
+}
+
+func (e *Equilibrium) active() []int {
+	active := []int{}
+	for i, formula := range e.parsed {
+		present := true
+		for _, atom := range formula {
+			if e.totals[slices.Index(e.elements, atom.Label)] <= e.tolerance {
+				present = false
+			}
+		}
+		if present {
+			active = append(active, i)
+		}
+	}
+	return active
+}
+
+func (e *Equilibrium) constraints(active []int) (*mat.Dense, []float64, error) {
+	parsed := make([][]chemformula.Atom, len(active))
+	for i, j := range active {
+		parsed[i] = e.parsed[j]
+	}
+	full := createReacMatrix(parsed)
+	elements := matrixElements(parsed)
+	b := make([]float64, len(elements))
+	for i, element := range elements {
+		b[i] = e.totals[slices.Index(e.elements, element)]
+	}
+
+	_, cols := full.Dims()
+	rows := []int{}
+	rank := 0
+	for i := range elements {
+		candidate := mat.NewDense(len(rows)+1, cols, nil)
+		for k, row := range append(append([]int{}, rows...), i) {
+			candidate.SetRow(k, full.RawRowView(row))
+		}
+		r, _, err := matrixRank(candidate, 1e-10)
+		if err != nil {
+			return nil, nil, err
+		}
+		if r > rank {
+			rows = append(rows, i)
+			rank = r
+		}
+	}
+
+	_, residual, err := utils.NNLS(full, b, 1e-12)
+	if err != nil {
+		return nil, nil, err
+	}
+	if residual > 1e-9*math.Max(utils.SumFloatS(b), 1) {
+		return nil, nil, fmt.Errorf("element totals %v can't be obtained from the species", b)
+	}
+
+	a := mat.NewDense(len(rows), cols, nil)
+	reduced := make([]float64, len(rows))
+	for k, row := range rows {
+		a.SetRow(k, full.RawRowView(row))
+		reduced[k] = b[row]
+	}
+	return a, reduced, nil
+}
+
+func (e *Equilibrium) Composition(temperature float64, pressure float64) (EquilibriumComposition, error) {
+	if temperature <= 0 {
+		return EquilibriumComposition{}, fmt.Errorf("temperature %v K should be > 0", temperature)
+	}
+	if pressure <= 0 {
+		return EquilibriumComposition{}, fmt.Errorf("pressure %v bar should be > 0", pressure)
+	}
+	active := e.active()
+	if len(active) == 0 {
+		return EquilibriumComposition{}, fmt.Errorf("there are no species for the element totals")
+	}
+	a, b, err := e.constraints(active)
+	if err != nil {
+		return EquilibriumComposition{}, err
+	}
+	m, n := a.Dims()
+
+	g := make([]float64, n)
+	gas := make([]bool, n)
+	for i, j := range active {
+		g[i] = e.species[j].Gibbs(temperature) * 1000 / (gasConstant * temperature)
+		gas[i] = e.species[j].Phase == gasPhase
+	}
+	lnP := math.Log(pressure)
+
+	gradient := func(x []float64, mu float64) []float64 {
+		gasTotal := 0.0
+		for i := range x {
+			if gas[i] {
+				gasTotal += x[i]
+			}
+		}
+		grad := make([]float64, n)
+		for i := range x {
+			grad[i] = g[i] - mu/x[i]
+			if gas[i] {
+				grad[i] += math.Log(x[i]/gasTotal) + lnP
+			}
+		}
+		return grad
+	}
+	residual := func(x []float64, nu []float64, mu float64) ([]float64, float64) {
+		r := make([]float64, n+m)
+		grad := gradient(x, mu)
+		for i := range n {
+			r[i] = grad[i]
+			for k := range m {
+				r[i] += a.At(k, i) * nu[k]
+			}
+		}
+		for k := range m {
+			r[n+k] = -b[k]
+			for i := range n {
+				r[n+k] += a.At(k, i) * x[i]
+			}
+		}
+		return r, mat.Norm(mat.NewVecDense(n+m, r), 2)
+	}
+
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = utils.SumFloatS(b) / float64(n)
+	}
+	nu := make([]float64, m)
+	lastMu := 1.0
+	for mu := 1.0; mu >= minBarrierWeight; mu /= 10 {
+		lastMu = mu
+		for range maxNewtonSteps {
+			r, norm := residual(x, nu, mu)
+			if norm < 1e-10 {
+				break
+			}
+			gasTotal := 0.0
+			for i := range x {
+				if gas[i] {
+					gasTotal += x[i]
+				}
+			}
+			kkt := mat.NewDense(n+m, n+m, nil)
+			for i := range n {
+				kkt.Set(i, i, mu/(x[i]*x[i]))
+				if gas[i] {
+					kkt.Set(i, i, kkt.At(i, i)+1/x[i])
+					for j := range n {
+						if gas[j] {
+							kkt.Set(i, j, kkt.At(i, j)-1/gasTotal)
+						}
+					}
+				}
+				for k := range m {
+					kkt.Set(i, n+k, a.At(k, i))
+					kkt.Set(n+k, i, a.At(k, i))
+				}
+			}
+			rhs := mat.NewVecDense(n+m, r)
+			rhs.ScaleVec(-1, rhs)
+			var step mat.VecDense
+			err := step.SolveVec(kkt, rhs)
+			var cond mat.Condition
+			if err != nil && !errors.As(err, &cond) {
+				return EquilibriumComposition{}, fmt.Errorf("can't solve the equilibrium at %v K: %w", temperature, err)
+			}
+
+			s := 1.0
+			for i := range n {
+				for x[i]+s*step.AtVec(i) <= 0 {
+					s /= 2
+				}
+			}
+			newX := make([]float64, n)
+			newNu := make([]float64, m)
+			for s > 1e-12 {
+				for i := range n {
+					newX[i] = x[i] + s*step.AtVec(i)
+				}
+				for k := range m {
+					newNu[k] = nu[k] + s*step.AtVec(n+k)
+				}
+				_, newNorm := residual(newX, newNu, mu)
+				if newNorm <= (1-0.01*s)*norm {
+					break
+				}
+				s /= 2
+			}
+			if s <= 1e-12 {
+				break
+			}
+			copy(x, newX)
+			copy(nu, newNu)
+		}
+	}
+	_, norm := residual(x, nu, lastMu)
+	if !(norm <= maxKKTResidual*math.Max(utils.SumFloatS(b), 1)) {
+		return EquilibriumComposition{}, fmt.Errorf("equilibrium at %v K did not converge", temperature)
+	}
+
+	moles := make([]float64, len(e.species))
+	for i, j := range active {
+		if x[i] > e.tolerance {
+			moles[j] = x[i]
+		}
+	}
+	gasTotal := 0.0
+	for i, d := range e.species {
+		if d.Phase == gasPhase {
+			gasTotal += moles[i]
+		}
+	}
+	gibbs := 0.0
+	species := make([]string, len(e.species))
+	phases := make([]string, len(e.species))
+	for i, d := range e.species {
+		species[i] = d.Formula
+		phases[i] = d.Phase
+		if moles[i] == 0 {
+			continue
+		}
+		gibbs += moles[i] * d.Gibbs(temperature)
+		if d.Phase == gasPhase {
+			gibbs += moles[i] * gasConstant * temperature * (math.Log(moles[i]/gasTotal) + lnP) / 1000
+		}
+	}
+
+	return EquilibriumComposition{
+		Temperature: temperature,
+		Pressure:    pressure,
+		Species:     species,
+		Phases:      phases,
+		Moles:       utils.RoundFloatS(moles, e.precision),
+		Gibbs:       utils.RoundFloat(gibbs, e.precision),
+	}, nil
+}
@@ -0,0 +1,171 @@
+package chemreaction
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func equilibriumTable(t *testing.T) *ThermoTable {
+	table, err := NewThermoTable([]ThermoData{
+		{Formula: "CaCO3", Phase: "s", Hf: -1207.6, S: 91.7},
+		{Formula: "CaO", Phase: "s", Hf: -634.9, S: 38.1},
+		{Formula: "CO2", Phase: "g", Hf: -393.5, S: 213.8},
+		{Formula: "NO2", Phase: "g", Hf: 33.18, S: 240.06},
+		{Formula: "N2O4", Phase: "g", Hf: 9.16, S: 304.29},
+		{Formula: "H2O", Phase: "l", Hf: -285.83, S: 69.95},
+		{Formula: "H2O", Phase: "g", Hf: -241.83, S: 188.84},
+		{Formula: "H2", Phase: "g", Hf: 0, S: 130.68},
+		{Formula: "O2", Phase: "g", Hf: 0, S: 205.15},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestEquilibriumCalcination(t *testing.T) {
+	table := equilibriumTable(t)
+	reaction, err := NewChemicalReaction("CaCO3=CaO+CO2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq, err := NewEquilibrium(reaction, []string{"CaCO3", "CaO", "CO2"}, table, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		temperature float64
+		moles       []float64
+	}{
+		{1000, []float64{1, 0, 0}},
+		{1300, []float64{0, 1, 1}},
+	}
+	for _, tt := range tests {
+		comp, err := eq.Composition(tt.temperature, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, moles := range tt.moles {
+			if math.Abs(comp.Moles[i]-moles) > 1e-6 {
+				t.Errorf("Composition(%v) = %v, expected %v", tt.temperature, comp.Moles, tt.moles)
+				break
+			}
+		}
+	}
+}
+
+func TestEquilibriumGas(t *testing.T) {
+	table := equilibriumTable(t)
+	opts := ReacOptions{Rmode: Force, Target: 0, TargerMass: 1, Intify: true, Precision: 8, Tolerance: 1e-8}
+	reaction, err := NewChemicalReaction("2NO2+N2O4=2N2O4", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq, err := NewEquilibrium(reaction, []string{"N2O4", "NO2"}, table, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pressure := range []float64{0.1, 1, 10} {
+		comp, err := eq.Composition(standardTemperature, pressure)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n2o4, no2 := comp.Moles[0], comp.Moles[1]
+		if math.Abs(2*n2o4+no2-4) > 1e-6 {
+			t.Errorf("nitrogen is not conserved: %v", comp.Moles)
+		}
+		total := n2o4 + no2
+		k := math.Pow(no2/total, 2) * pressure / (n2o4 / total)
+		dg := 2*33.18 - 9.16 - standardTemperature*(2*240.06-304.29)/1000
+		expected := math.Exp(-dg * 1000 / (gasConstant * standardTemperature))
+		if math.Abs(k-expected) > 1e-5 {
+			t.Errorf("K(%v bar) = %v, expected %v", pressure, k, expected)
+		}
+	}
+}
+
+func TestEquilibriumPhases(t *testing.T) {
+	table := equilibriumTable(t)
+	reaction, err := NewChemicalReaction("2H2+O2=2H2O")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq, err := NewEquilibrium(reaction, []string{"H2O", "H2", "O2"}, table, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eq.Species()) != 4 {
+		t.Fatalf("expected 4 species, got %v", eq.Species())
+	}
+	tests := []struct {
+		temperature float64
+		liquid      float64
+		gas         float64
+	}{
+		{350, 2, 0},
+		{400, 0, 2},
+	}
+	for _, tt := range tests {
+		comp, err := eq.Composition(tt.temperature, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(comp.Moles[0]-tt.liquid) > 1e-6 || math.Abs(comp.Moles[1]-tt.gas) > 1e-6 {
+			t.Errorf("Composition(%v) = %v", tt.temperature, comp)
+		}
+	}
+}
+
+func TestEquilibriumErrors(t *testing.T) {
+	table := equilibriumTable(t)
+	reaction, err := NewChemicalReaction("CaCO3=CaO+CO2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewEquilibrium(reaction, []string{"CaO", "CO2"}, table, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewEquilibrium(reaction, []string{"CaO", "NO2"}, table, nil)
+	if err == nil || !strings.Contains(err.Error(), "element C") {
+		t.Errorf("expected an error for missing carbon, got %v", err)
+	}
+	_, err = NewEquilibrium(reaction, []string{"CaCO3", "BaO"}, table, nil)
+	if err == nil {
+		t.Error("expected an error for missing data")
+	}
+
+	eq, err := NewEquilibrium(reaction, []string{"CaCO3"}, table, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = eq.Composition(-1, 1)
+	if err == nil {
+		t.Error("expected an error for negative temperature")
+	}
+	comp, err := eq.Composition(1300, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comp.Moles[0] != 1 {
+		t.Errorf("Composition() = %v, expected [1]", comp.Moles)
+	}
+
+	broken, err := NewThermoTable([]ThermoData{
+		{Formula: "CaCO3", Phase: "s", Hf: -1207.6, S: 91.7},
+		{Formula: "CaO", Phase: "s", Hf: -634.9, S: 38.1},
+		{Formula: "CO2", Phase: "g", Hf: 1e306, S: 213.8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq, err = NewEquilibrium(reaction, []string{"CaCO3", "CaO", "CO2"}, broken, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = eq.Composition(1300, 1)
+	if err == nil || !strings.Contains(err.Error(), "did not converge") {
+		t.Errorf("expected a convergence error, got %v", err)
+	}
+}
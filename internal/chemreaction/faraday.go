@@ -0,0 +1,253 @@
+package chemreaction
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+const electronSymbol = "e"
+
+func extractElectrons(reaction string) (string, float64, error) {
+	decomp, err := newReactionDecomposer(reaction)
+	if err != nil {
+		return "", 0, err
+	}
+	electrons := 0.0
+	sides := [2][]string{}
+	for i, compound := range decomp.compounds {
+		side := 0
+		if i >= decomp.separatorPos {
+			side = 1
+		}
+		if compound == electronSymbol {
+			if side == 0 {
+				electrons += decomp.initCoefs[i]
+			} else {
+				electrons -= decomp.initCoefs[i]
+			}
+			continue
+		}
+		if decomp.initCoefs[i] != 1 {
+			compound = formatFloat(decomp.initCoefs[i]) + compound
+		}
+		sides[side] = append(sides[side], compound)
+	}
+	if electrons == 0 {
+		return reaction, 0, nil
+	}
+	neutral := strings.Join(sides[0], reactionRegexes.reactantSeparator) +
+		decomp.separator +
+		strings.Join(sides[1], reactionRegexes.reactantSeparator)
+	if electrons < 0 {
+		electrons = -electrons
+	}
+	return neutral, electrons, nil
+}
+
+type Electrolysis struct {
+	reaction   *ChemicalReaction
+	electrons  float64
+	efficiency float64
+	precision  uint
+}
+
+func NewElectrolysis(reaction string, electrons float64, efficiency float64, options ...ReacOptions) (*Electrolysis, error) {
+	if efficiency <= 0 || efficiency > 1 {
+		return nil, fmt.Errorf("current efficiency %v should be in range (0, 1]", efficiency)
+	}
+	if electrons < 0 {
+		return nil, fmt.Errorf("negative number of electrons %v", electrons)
+	}
+
+	var reacOpt ReacOptions
+	if options == nil {
+		reacOpt = ReacOptions{
+			Rmode:      Balance,
+			Target:     0,
+			TargerMass: 1.0,
+			Intify:     true,
+			Precision:  8,
+			Tolerance:  1e-8,
+		}
+	} else {
+		reacOpt = options[0]
+	}
+
+	neutral, perReaction, err := extractElectrons(strings.Replace(reaction, " ", "", -1))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case perReaction > 0 && electrons > 0:
+		return nil, fmt.Errorf("electrons are set both in the reaction and as a number")
+	case perReaction > 0:
+		reacOpt.Rmode = Check
+	case electrons == 0:
+		return nil, fmt.Errorf("no electrons in the reaction '%s' and the number of electrons is 0", reaction)
+	}
+
+	reac, err := NewChemicalReaction(neutral, reacOpt)
+	if err != nil {
+		return nil, err
+	}
+	if perReaction > 0 {
+		target, err := reac.calculatedTarget()
+		if err != nil {
+			return nil, err
+		}
+		electrons = perReaction / reac.decomposer.initCoefs[target]
+	}
+
+	return &Electrolysis{
+		reaction:   reac,
+		electrons:  electrons,
+		efficiency: efficiency,
+		precision:  reacOpt.Precision,
+	}, nil
+}
+
+func (e *Electrolysis) Reaction() *ChemicalReaction {
+	return e.reaction
+}
+
+func (e *Electrolysis) Electrons() float64 {
+	return e.electrons
+}
+
+func (e *Electrolysis) targetMolarMass() (float64, error) {
+	molars, err := e.reaction.MolarMasses()
+	if err != nil {
+		return 0, err
+	}
+	target, err := e.reaction.calculatedTarget()
+	if err != nil {
+		return 0, err
+	}
+	return molars[target], nil
+}
+
+func (e *Electrolysis) Masses() ([]float64, error) {
+	return e.reaction.Masses()
+}
+
+func (e *Electrolysis) Charge() (float64, error) {
+	molar, err := e.targetMolarMass()
+	if err != nil {
+		return 0, err
+	}
+	moles := e.reaction.reacOpts.TargerMass / molar
+	return utils.RoundFloat(e.electrons*chemformula.FaradayConstant*moles/e.efficiency, e.precision), nil
+}
+
+func (e *Electrolysis) ChargeAh() (float64, error) {
+	charge, err := e.Charge()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(charge/3600, e.precision), nil
+}
+
+func (e *Electrolysis) Time(current float64) (float64, error) {
+	if current <= 0 {
+		return 0, fmt.Errorf("current %v should be > 0", current)
+	}
+	charge, err := e.Charge()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(charge/current, e.precision), nil
+}
+
+func (e *Electrolysis) Current(time float64) (float64, error) {
+	if time <= 0 {
+		return 0, fmt.Errorf("time %v should be > 0", time)
+	}
+	charge, err := e.Charge()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(charge/time, e.precision), nil
+}
+
+func (e *Electrolysis) DepositedMass(current float64, time float64) (float64, error) {
+	if current < 0 || time < 0 {
+		return 0, fmt.Errorf("current %v and time %v should be >= 0", current, time)
+	}
+	molar, err := e.targetMolarMass()
+	if err != nil {
+		return 0, err
+	}
+	moles := current * time * e.efficiency / (e.electrons * chemformula.FaradayConstant)
+	return utils.RoundFloat(moles*molar, e.precision), nil
+}
+
+func (e *Electrolysis) Output(printPrecision ...uint) (elOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	final, err := e.reaction.FinalReactionNorm()
+	if err != nil {
+		return elOutput{}, err
+	}
+	masses, err := e.Masses()
+	if err != nil {
+		return elOutput{}, err
+	}
+	charge, err := e.Charge()
+	if err != nil {
+		return elOutput{}, err
+	}
+	chargeAh, err := e.ChargeAh()
+	if err != nil {
+		return elOutput{}, err
+	}
+
+	return elOutput{
+		FinalReaction: final,
+		Electrons:     e.electrons,
+		Efficiency:    e.efficiency,
+		TargetMass:    e.reaction.reacOpts.TargerMass,
+		Charge:        utils.RoundFloat(charge, pPrecision),
+		ChargeAh:      utils.RoundFloat(chargeAh, pPrecision),
+		Compounds:     e.reaction.decomposer.compounds,
+		Masses:        utils.RoundFloatS(masses, pPrecision),
+	}, nil
+}
+
+type elOutput struct {
+	FinalReaction string
+	Electrons     float64
+	Efficiency    float64
+	TargetMass    float64
+	Charge        float64
+	ChargeAh      float64
+	Compounds     []string
+	Masses        []float64
+}
+
+func (o elOutput) String() string {
+	out := fmt.Sprintln("final reaction:", o.FinalReaction) +
+		fmt.Sprintln("electrons per target:", o.Electrons) +
+		fmt.Sprintln("current efficiency:", o.Efficiency) +
+		fmt.Sprintln("target mass:", o.TargetMass) +
+		fmt.Sprintf("charge: %v C (%v Ah)\n", o.Charge, o.ChargeAh)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	for i, compound := range o.Compounds {
+		fmt.Fprintf(w, "%s\tm = %v\tg\n", compound, o.Masses[i])
+	}
+
+	w.Flush()
+	return out + strings.TrimSuffix(buf.String(), "\n")
+}
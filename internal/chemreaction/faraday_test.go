@@ -0,0 +1,102 @@
+package chemreaction
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+)
+
+func TestExtractElectrons(t *testing.T) {
+	tests := []struct {
+		reaction  string
+		neutral   string
+		electrons float64
+	}{
+		{"CuSO4+2e=Cu+SO4", "CuSO4=Cu+SO4", 2},
+		{"2H2O=O2+4H+4e", "2H2O=O2+4H", 4},
+		{"CuSO4+H2O=Cu+H2SO4+O2", "CuSO4+H2O=Cu+H2SO4+O2", 0},
+	}
+	for _, tt := range tests {
+		neutral, electrons, err := extractElectrons(tt.reaction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if neutral != tt.neutral || electrons != tt.electrons {
+			t.Errorf("extractElectrons(%s) = %s, %v, expected %s, %v",
+				tt.reaction, neutral, electrons, tt.neutral, tt.electrons)
+		}
+	}
+}
+
+func TestElectrolysis(t *testing.T) {
+	tests := []struct {
+		name      string
+		reaction  string
+		electrons float64
+		target    int
+	}{
+		{"half-reaction", "CuSO4+2e=Cu+SO4", 0, 0},
+		{"full reaction", "CuSO4+H2O=Cu+H2SO4+O2", 2, 0},
+		{"scaled half-reaction", "2CuSO4+4e=2Cu+2SO4", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := ReacOptions{Rmode: Balance, Target: tt.target, TargerMass: 1, Intify: true, Precision: 8, Tolerance: 1e-8}
+			el, err := NewElectrolysis(tt.reaction, tt.electrons, 0.9, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			charge, err := el.Charge()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := 2 * chemformula.FaradayConstant / 63.546 / 0.9
+			if math.Abs(charge-expected) > 1e-3 {
+				t.Errorf("Charge() = %v, expected %v", charge, expected)
+			}
+			mass, err := el.DepositedMass(1, charge)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(mass-1) > 1e-6 {
+				t.Errorf("DepositedMass() = %v, expected 1", mass)
+			}
+			time, err := el.Time(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(time-charge/2) > 1e-6 {
+				t.Errorf("Time() = %v, expected %v", time, charge/2)
+			}
+		})
+	}
+}
+
+func TestNewElectrolysis_invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		reaction   string
+		electrons  float64
+		efficiency float64
+	}{
+		{"no electrons", "CuSO4+H2O=Cu+H2SO4+O2", 0, 1},
+		{"electrons twice", "CuSO4+2e=Cu+SO4", 2, 1},
+		{"zero efficiency", "CuSO4+2e=Cu+SO4", 0, 0},
+		{"unbalanced half-reaction", "CuSO4+2e=Cu+SO3", 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			el, err := NewElectrolysis(tt.reaction, tt.electrons, tt.efficiency)
+			if err == nil {
+				_, err = el.Charge()
+			}
+			if err == nil {
+				_, err = el.Masses()
+			}
+			if err == nil {
+				t.Errorf("NewElectrolysis(%s) expected error", tt.reaction)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package chemreaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+var mixedSiteRegex = regexp.MustCompile(`\(([A-Z][a-z]*(?:,[A-Z][a-z]*)+)\)`)
+
+type GridPoint struct {
+	Fractions []float64
+	Formula   string
+	Reaction  string
+	Masses    []float64
+	Volumes   []float64
+	Error     string
+}
+
+type bound struct {
+	min float64
+	max float64
+}
+
+type CompositionGrid struct {
+	formula        string
+	site           []string
+	step           float64
+	divisions      int
+	precursors     []string
+	byproducts     []string
+	reacOpts       ReacOptions
+	bounds         map[string]bound
+	exclusions     []func(fractions map[string]float64) bool
+	concentrations map[string]float64
+	points         *[]GridPoint
+}
+
+func NewCompositionGrid(formula string, step float64, precursors []string, byproducts []string, options ...ReacOptions) (*CompositionGrid, error) {
+	newFormula := strings.Replace(formula, " ", "", -1)
+	sites := mixedSiteRegex.FindAllStringSubmatch(newFormula, -1)
+	if len(sites) != 1 {
+		return nil, fmt.Errorf("formula '%s' should contain exactly one mixed site like (Fe,Co,Ni)", newFormula)
+	}
+	site := strings.Split(sites[0][1], ",")
+	if len(utils.UniqueElems(site)) != len(site) {
+		return nil, fmt.Errorf("repeated elements in the mixed site %s", sites[0][0])
+	}
+
+	if step <= 0 || step > 1 {
+		return nil, fmt.Errorf("grid step %v should be in range (0, 1]", step)
+	}
+	divisions := int(math.Round(1 / step))
+	if math.Abs(float64(divisions)*step-1) > 1e-9 {
+		return nil, fmt.Errorf("grid step %v should divide 1 into an integer number of parts", step)
+	}
+	if len(precursors) == 0 {
+		return nil, fmt.Errorf("no precursors for the composition grid")
+	}
+
+	var reacOpt ReacOptions
+	if options == nil {
+		reacOpt = ReacOptions{
+			Rmode:      Balance,
+			Target:     0,
+			TargerMass: 1.0,
+			Intify:     true,
+			Precision:  8,
+			Tolerance:  1e-8,
+		}
+	} else {
+		reacOpt = options[0]
+	}
+	reacOpt.Target = 0
+
+	return &CompositionGrid{
+		formula:        newFormula,
+		site:           site,
+		step:           step,
+		divisions:      divisions,
+		precursors:     precursors,
+		byproducts:     byproducts,
+		reacOpts:       reacOpt,
+		bounds:         map[string]bound{},
+		concentrations: map[string]float64{},
+	}, nil
+}
+
+func (g *CompositionGrid) Elements() []string {
+	return g.site
+}
+
+func (g *CompositionGrid) Compounds() []string {
+	compounds := append([]string{}, g.precursors...)
+	compounds = append(compounds, g.formula)
+	return append(compounds, g.byproducts...)
+}
+
+func (g *CompositionGrid) SetBounds(element string, min, max float64) error {
+	if !slices.Contains(g.site, element) {
+		return fmt.Errorf("there is no element %s in the mixed site %v", element, g.site)
+	}
+	if min > max {
+		return fmt.Errorf("lower bound %v of %s is greater than upper bound %v", min, element, max)
+	}
+	g.bounds[element] = bound{min: min, max: max}
+	g.points = nil
+	return nil
+}
+
+func (g *CompositionGrid) Exclude(region func(fractions map[string]float64) bool) {
+	g.exclusions = append(g.exclusions, region)
+	g.points = nil
+}
+
+func (g *CompositionGrid) SetConcentrations(concentrations map[string]float64) error {
+	for precursor, c := range concentrations {
+		if !slices.Contains(g.precursors, precursor) {
+			return fmt.Errorf("there is no precursor %s in the composition grid", precursor)
+		}
+		if c <= 0 {
+			return fmt.Errorf("concentration %v of %s should be > 0", c, precursor)
+		}
+	}
+	g.concentrations = concentrations
+	g.points = nil
+	return nil
+}
+
+func (g *CompositionGrid) fractions() [][]float64 {
+	grid := [][]float64{}
+	counts := make([]int, len(g.site))
+	var fill func(pos, rest int)
+	fill = func(pos, rest int) {
+		if pos == len(counts)-1 {
+			counts[pos] = rest
+			fractions := make([]float64, len(counts))
+			for i, c := range counts {
+				fractions[i] = utils.RoundFloat(float64(c)/float64(g.divisions), 10)
+			}
+			grid = append(grid, fractions)
+			return
+		}
+		for c := 0; c <= rest; c++ {
+			counts[pos] = c
+			fill(pos+1, rest-c)
+		}
+	}
+	fill(0, g.divisions)
+	return grid
+}
+
+func (g *CompositionGrid) included(fractions []float64) bool {
+	values := make(map[string]float64, len(g.site))
+	for i, el := range g.site {
+		values[el] = fractions[i]
+		if b, ok := g.bounds[el]; ok && (fractions[i] < b.min-1e-9 || fractions[i] > b.max+1e-9) {
+			return false
+		}
+	}
+	for _, excluded := range g.exclusions {
+		if excluded(values) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *CompositionGrid) pointFormula(fractions []float64) string {
+	var b strings.Builder
+	for i, el := range g.site {
+		switch fractions[i] {
+		case 0:
+		case 1:
+			return mixedSiteRegex.ReplaceAllLiteralString(g.formula, el)
+		default:
+			b.WriteString(el + formatFloat(fractions[i]))
+		}
+	}
+	return mixedSiteRegex.ReplaceAllLiteralString(g.formula, "("+b.String()+")")
+}
+
+func (g *CompositionGrid) point(fractions []float64) GridPoint {
+	p := GridPoint{Fractions: fractions, Formula: g.pointFormula(fractions)}
+	products := append([]string{p.Formula}, g.byproducts...)
+	reaction := strings.Join(g.precursors, reactionRegexes.reactantSeparator) + "=" +
+		strings.Join(products, reactionRegexes.reactantSeparator)
+
+	m, err := balanceMember(reaction, g.reacOpts)
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	p.Reaction = m.reaction
+	p.Masses = m.masses
+	p.Volumes = make([]float64, len(g.precursors))
+	for i, precursor := range g.precursors {
+		if c, ok := g.concentrations[precursor]; ok && m.molarMasses[i] > 0 {
+			p.Volumes[i] = utils.RoundFloat(m.masses[i]/m.molarMasses[i]/c*1000, g.reacOpts.Precision)
+		}
+	}
+	return p
+}
+
+func (g *CompositionGrid) Points() []GridPoint {
+	if g.points == nil {
+		points := []GridPoint{}
+		for _, fractions := range g.fractions() {
+			if g.included(fractions) {
+				points = append(points, g.point(fractions))
+			}
+		}
+		g.points = &points
+	}
+	return *g.points
+}
+
+func (g *CompositionGrid) Output(printPrecision ...uint) gridOutput {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	points := g.Points()
+	pPoints := make([]GridPoint, len(points))
+	for i, p := range points {
+		pPoints[i] = p
+		pPoints[i].Masses = utils.RoundFloatS(p.Masses, pPrecision)
+		pPoints[i].Volumes = utils.RoundFloatS(p.Volumes, pPrecision)
+	}
+
+	solutions := []string{}
+	for _, precursor := range g.precursors {
+		if _, ok := g.concentrations[precursor]; ok {
+			solutions = append(solutions, precursor)
+		}
+	}
+
+	return gridOutput{
+		Formula:    g.formula,
+		Step:       g.step,
+		TargetMass: g.reacOpts.TargerMass,
+		Elements:   g.site,
+		Compounds:  g.Compounds(),
+		Solutions:  solutions,
+		Points:     pPoints,
+	}
+}
+
+type gridOutput struct {
+	Formula    string
+	Step       float64
+	TargetMass float64
+	Elements   []string
+	Compounds  []string
+	Solutions  []string
+	Points     []GridPoint
+}
+
+func (o gridOutput) table() table {
+	t := table{header: append([]string{}, o.Elements...)}
+	t.header = append(t.header, o.Compounds...)
+	for _, solution := range o.Solutions {
+		t.header = append(t.header, "V("+solution+"), mL")
+	}
+	t.header = append(t.header, "reaction")
+
+	for _, p := range o.Points {
+		line := []string{}
+		for _, f := range p.Fractions {
+			line = append(line, formatFloat(f))
+		}
+		for i := range o.Compounds {
+			if p.Error != "" {
+				line = append(line, "-")
+			} else {
+				line = append(line, formatFloat(p.Masses[i]))
+			}
+		}
+		for _, solution := range o.Solutions {
+			i := slices.Index(o.Compounds, solution)
+			if p.Error != "" {
+				line = append(line, "-")
+			} else {
+				line = append(line, formatFloat(p.Volumes[i]))
+			}
+		}
+		if p.Error != "" {
+			line = append(line, "error: "+p.Error)
+		} else {
+			line = append(line, p.Reaction)
+		}
+		t.rows = append(t.rows, line)
+	}
+	return t
+}
+
+func (o gridOutput) String() string {
+	return fmt.Sprintln("formula:", o.Formula) +
+		fmt.Sprintln("step:", o.Step) +
+		fmt.Sprintln("target mass:", o.TargetMass) +
+		o.table().String()
+}
+
+func (o gridOutput) CSV() (string, error) {
+	return o.table().CSV()
+}
+
+func (o gridOutput) JSON() ([]byte, error) {
+	return json.Marshal(o)
+}
@@ -0,0 +1,96 @@
+package chemreaction
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNewCompositionGrid_invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		formula    string
+		step       float64
+		precursors []string
+	}{
+		{"no mixed site", "Fe3O4", 0.1, []string{"Fe2O3"}},
+		{"two mixed sites", "(Fe,Co)(Cr,Al)2O4", 0.1, []string{"Fe2O3"}},
+		{"repeated element", "(Fe,Fe)3O4", 0.1, []string{"Fe2O3"}},
+		{"step does not divide 1", "(Fe,Co)3O4", 0.3, []string{"Fe2O3"}},
+		{"no precursors", "(Fe,Co)3O4", 0.1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCompositionGrid(tt.formula, tt.step, tt.precursors, nil); err == nil {
+				t.Errorf("NewCompositionGrid(%s, %v) expected error", tt.formula, tt.step)
+			}
+		})
+	}
+}
+
+func TestCompositionGrid_Points(t *testing.T) {
+	opts := ReacOptions{Rmode: Balance, Target: 0, TargerMass: 2, Intify: true, Precision: 8, Tolerance: 1e-8}
+	grid, err := NewCompositionGrid("(Fe,Co,Ni)3O4", 0.05,
+		[]string{"Fe(NO3)3", "Co(NO3)2", "Ni(NO3)2"}, []string{"NO2", "O2"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(grid.Points()); got != 231 {
+		t.Errorf("len(Points()) = %d, expected 231", got)
+	}
+
+	grid.Exclude(func(f map[string]float64) bool { return f["Ni"] > 0.5 })
+	err = grid.SetBounds("Fe", 0.1, 0.9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range grid.Points() {
+		if p.Fractions[2] > 0.5 || p.Fractions[0] < 0.1 || p.Fractions[0] > 0.9 {
+			t.Errorf("point %v is out of the allowed region", p.Fractions)
+		}
+		if p.Error != "" {
+			t.Errorf("point %v: %s", p.Fractions, p.Error)
+		}
+	}
+}
+
+func TestCompositionGrid_endMembers(t *testing.T) {
+	opts := ReacOptions{Rmode: Balance, Target: 0, TargerMass: 2, Intify: true, Precision: 8, Tolerance: 1e-8}
+	grid, _ := NewCompositionGrid("(Fe,Co)3O4", 0.5, []string{"Fe(NO3)3", "Co(NO3)2"}, []string{"NO2", "O2"}, opts)
+	err := grid.SetConcentrations(map[string]float64{"Co(NO3)2": 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := grid.Points()
+	expected := []string{"Co3O4", "(Fe0.5Co0.5)3O4", "Fe3O4"}
+	for i, p := range points {
+		if p.Formula != expected[i] {
+			t.Errorf("Points()[%d].Formula = %s, expected %s", i, p.Formula, expected[i])
+		}
+	}
+	if points[0].Reaction != "3Co(NO3)2=Co3O4+6NO2+O2" || points[0].Masses[0] != 0 {
+		t.Errorf("Points()[0] = %v", points[0])
+	}
+	if points[0].Volumes[0] != 0 || math.Abs(points[0].Volumes[1]-49.8348) > 1e-4 {
+		t.Errorf("Points()[0].Volumes = %v", points[0].Volumes)
+	}
+
+	csv, err := grid.Output().CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := "Fe,Co,Fe(NO3)3,Co(NO3)2,\"(Fe,Co)3O4\",NO2,O2,\"V(Co(NO3)2), mL\",reaction\n"
+	if !strings.HasPrefix(csv, header) {
+		t.Errorf("CSV() = %s", csv)
+	}
+}
+
+func TestCompositionGrid_SetConcentrations(t *testing.T) {
+	grid, _ := NewCompositionGrid("(Fe,Co)3O4", 0.5, []string{"Fe(NO3)3", "Co(NO3)2"}, []string{"NO2", "O2"})
+	if err := grid.SetConcentrations(map[string]float64{"Ni(NO3)2": 0.5}); err == nil {
+		t.Error("expected error for unknown precursor")
+	}
+	if err := grid.SetConcentrations(map[string]float64{"Co(NO3)2": 0}); err == nil {
+		t.Error("expected error for zero concentration")
+	}
+}
@@ -1,6 +1,8 @@
 package chemreaction
 
 import (
+	"slices"
+
 	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
 	"gonum.org/v1/gonum/mat"
 )
@@ -31,3 +33,15 @@ func createReacMatrix(parsedFormulas [][]chemformula.Atom) *mat.Dense {
 
 	return mat.NewDense(numAtoms, numFormulas, data)
 }
+
+func matrixElements(parsedFormulas [][]chemformula.Atom) []string {
+	elements := []string{}
+	for _, formula := range parsedFormulas {
+		for _, atom := range formula {
+			if !slices.Contains(elements, atom.Label) {
+				elements = append(elements, atom.Label)
+			}
+		}
+	}
+	return elements
+}
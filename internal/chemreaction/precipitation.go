@@ -0,0 +1,350 @@
+package chemreaction
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+type ion struct {
+	formula string
+	charge  int64
+}
+
+type precipitant struct {
+	cation ion
+	anion  ion
+}
+
+var saltAnions = []ion{
+	{"NO3", 1},
+	{"SO4", 2},
+	{"Cl", 1},
+	{"Br", 1},
+	{"I", 1},
+	{"ClO4", 1},
+	{"C2H3O2", 1},
+}
+
+var precipitants = map[string]precipitant{
+	"NaOH":       {ion{"Na", 1}, ion{"OH", 1}},
+	"KOH":        {ion{"K", 1}, ion{"OH", 1}},
+	"LiOH":       {ion{"Li", 1}, ion{"OH", 1}},
+	"NH4OH":      {ion{"NH4", 1}, ion{"OH", 1}},
+	"Na2CO3":     {ion{"Na", 1}, ion{"CO3", 2}},
+	"K2CO3":      {ion{"K", 1}, ion{"CO3", 2}},
+	"(NH4)2CO3":  {ion{"NH4", 1}, ion{"CO3", 2}},
+	"C2H2O4":     {ion{"H", 1}, ion{"C2O4", 2}},
+	"(NH4)2C2O4": {ion{"NH4", 1}, ion{"C2O4", 2}},
+	"Na2C2O4":    {ion{"Na", 1}, ion{"C2O4", 2}},
+}
+
+func elementSet(atoms []chemformula.Atom) []string {
+	labels := []string{}
+	for _, atom := range atoms {
+		labels = append(labels, atom.Label)
+	}
+	labels = utils.UniqueElems(labels)
+	slices.Sort(labels)
+	return labels
+}
+
+func ionGroup(formula string, n int64) string {
+	if n == 1 {
+		return formula
+	}
+	atoms := parseFormula(formula)
+	if len(atoms) > 1 || atoms[0].Amount != 1 {
+		return fmt.Sprintf("(%s)%d", formula, n)
+	}
+	return fmt.Sprintf("%s%d", formula, n)
+}
+
+func ionicFormula(cation ion, anion ion) string {
+	gcd := utils.FindGCDSliceInt64([]int64{cation.charge, anion.charge})
+	return ionGroup(cation.formula, anion.charge/gcd) + ionGroup(anion.formula, cation.charge/gcd)
+}
+
+type saltComposition struct {
+	metal       string
+	metalAmount float64
+	anion       ion
+	anionAmount float64
+}
+
+func decomposeSalt(salt string) (saltComposition, error) {
+	var comp saltComposition
+	nonMetals := []chemformula.Atom{}
+	adducts := chemformula.AdductSymbols()
+	for _, atom := range parseFormula(strings.FieldsFunc(salt, func(r rune) bool {
+		return slices.Contains(adducts, r)
+	})[0]) {
+		if chemformula.IsMetal(atom.Label) {
+			if comp.metal != "" && comp.metal != atom.Label {
+				return comp, fmt.Errorf("salt %s contains more than one metal", salt)
+			}
+			comp.metal = atom.Label
+			comp.metalAmount += atom.Amount
+		} else {
+			nonMetals = append(nonMetals, atom)
+		}
+	}
+	if comp.metal == "" {
+		return comp, fmt.Errorf("there is no metal in the salt %s", salt)
+	}
+	set := elementSet(nonMetals)
+	for _, anion := range saltAnions {
+		anionAtoms := parseFormula(anion.formula)
+		if slices.Equal(set, elementSet(anionAtoms)) {
+			comp.anion = anion
+			for _, atom := range nonMetals {
+				if atom.Label == anionAtoms[0].Label {
+					comp.anionAmount += atom.Amount / anionAtoms[0].Amount
+				}
+			}
+			return comp, nil
+		}
+	}
+	return comp, fmt.Errorf("unknown anion of the salt %s", salt)
+}
+
+type Precipitation struct {
+	precursors  []string
+	precipitant string
+	precipitate string
+	byproducts  []string
+	excess      float64
+	reacOpts    ReacOptions
+	reaction    *ChemicalReaction
+	spectators  []string
+}
+
+func NewPrecipitation(precursors []string, precipitantFormula string, precipitate string, excess float64, options ...ReacOptions) (*Precipitation, error) {
+	if len(precursors) == 0 {
+		return nil, fmt.Errorf("no precursors for the precipitation")
+	}
+	if excess < 0 {
+		return nil, fmt.Errorf("negative excess %v of the precipitant", excess)
+	}
+	newPrecipitant := strings.Replace(precipitantFormula, " ", "", -1)
+	prec, ok := precipitants[newPrecipitant]
+	if !ok {
+		return nil, fmt.Errorf("unknown precipitant %s", precipitantFormula)
+	}
+
+	newPrecursors := make([]string, len(precursors))
+	byproducts := []string{}
+	var salt saltComposition
+	for i, precursor := range precursors {
+		newPrecursors[i] = strings.Replace(precursor, " ", "", -1)
+		_, err := chemformula.NewChemicalFormula(newPrecursors[i])
+		if err != nil {
+			return nil, err
+		}
+		salt, err = decomposeSalt(newPrecursors[i])
+		if err != nil {
+			return nil, err
+		}
+		byproduct := ionicFormula(prec.cation, salt.anion)
+		if !slices.Contains(byproducts, byproduct) {
+			byproducts = append(byproducts, byproduct)
+		}
+	}
+
+	newPrecipitate := strings.Replace(precipitate, " ", "", -1)
+	if newPrecipitate == "" {
+		if len(precursors) > 1 {
+			return nil, fmt.Errorf("precipitate formula is required for co-precipitation")
+		}
+		valence := float64(salt.anion.charge) * salt.anionAmount / salt.metalAmount
+		if math.Abs(valence-math.Round(valence)) > 1e-9 {
+			return nil, fmt.Errorf("valence %v of %s in %s is not a whole number, precipitate formula is required", valence, salt.metal, newPrecursors[0])
+		}
+		newPrecipitate = ionicFormula(ion{salt.metal, int64(math.Round(valence))}, prec.anion)
+	}
+	_, err := chemformula.NewChemicalFormula(newPrecipitate)
+	if err != nil {
+		return nil, err
+	}
+
+	var reacOpt ReacOptions
+	if options == nil {
+		reacOpt = ReacOptions{
+			Rmode:      Balance,
+			Target:     0,
+			TargerMass: 1.0,
+			Intify:     true,
+			Precision:  8,
+			Tolerance:  1e-8,
+		}
+	} else {
+		reacOpt = options[0]
+	}
+	reacOpt.Rmode = Balance
+	reacOpt.Target = 0
+
+	return &Precipitation{
+		precursors:  newPrecursors,
+		precipitant: newPrecipitant,
+		precipitate: newPrecipitate,
+		byproducts:  byproducts,
+		excess:      excess,
+		reacOpts:    reacOpt,
+	}, nil
+}
+
+func (p *Precipitation) Precipitate() string {
+	return p.precipitate
+}
+
+func (p *Precipitation) Byproducts() []string {
+	return p.byproducts
+}
+
+func (p *Precipitation) Reaction() (*ChemicalReaction, error) {
+	if p.reaction == nil {
+		reactants := strings.Join(append(append([]string{}, p.precursors...), p.precipitant), reactionRegexes.reactantSeparator)
+		products := append([]string{p.precipitate}, p.byproducts...)
+		var errs []error
+		for _, extra := range [][]string{nil, {"H2O"}, {"NH3"}, {"NH3", "H2O"}, {"CO2"}, {"CO2", "H2O"}} {
+			reaction := reactants + "=" + strings.Join(append(products, extra...), reactionRegexes.reactantSeparator)
+			reac, err := NewChemicalReaction(reaction, p.reacOpts)
+			if err == nil {
+				_, err = reac.Masses()
+			}
+			if err == nil {
+				p.reaction = reac
+				p.spectators = append([]string{}, extra...)
+				return reac, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, fmt.Errorf("can't generate the precipitation reaction of %s: %w", p.precipitate, errs[0])
+	}
+	return p.reaction, nil
+}
+
+func (p *Precipitation) Spectators() ([]string, error) {
+	_, err := p.Reaction()
+	if err != nil {
+		return nil, err
+	}
+	return p.spectators, nil
+}
+
+func (p *Precipitation) masses() ([]float64, error) {
+	reac, err := p.Reaction()
+	if err != nil {
+		return nil, err
+	}
+	return reac.Masses()
+}
+
+func (p *Precipitation) StoichiometricPrecipitantMass() (float64, error) {
+	masses, err := p.masses()
+	if err != nil {
+		return 0, err
+	}
+	return masses[len(p.precursors)], nil
+}
+
+func (p *Precipitation) PrecipitantMass() (float64, error) {
+	mass, err := p.StoichiometricPrecipitantMass()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(mass*(1+p.excess), p.reacOpts.Precision), nil
+}
+
+func (p *Precipitation) ByproductMasses() ([]float64, error) {
+	masses, err := p.masses()
+	if err != nil {
+		return nil, err
+	}
+	return masses[len(p.precursors)+2:], nil
+}
+
+func (p *Precipitation) Yield() (float64, error) {
+	masses, err := p.masses()
+	if err != nil {
+		return 0, err
+	}
+	return masses[len(p.precursors)+1], nil
+}
+
+func (p *Precipitation) Output(printPrecision ...uint) (ppOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	reac, err := p.Reaction()
+	if err != nil {
+		return ppOutput{}, err
+	}
+	out, err := reac.Output(pPrecision)
+	if err != nil {
+		return ppOutput{}, err
+	}
+	mass, err := p.PrecipitantMass()
+	if err != nil {
+		return ppOutput{}, err
+	}
+	byproducts, err := p.ByproductMasses()
+	if err != nil {
+		return ppOutput{}, err
+	}
+	spectators, err := p.Spectators()
+	if err != nil {
+		return ppOutput{}, err
+	}
+	yield, err := p.Yield()
+	if err != nil {
+		return ppOutput{}, err
+	}
+
+	return ppOutput{
+		Reaction:        out,
+		Precipitant:     p.precipitant,
+		Excess:          p.excess,
+		PrecipitantMass: utils.RoundFloat(mass, pPrecision),
+		Byproducts:      append(append([]string{}, p.byproducts...), spectators...),
+		ByproductMasses: utils.RoundFloatS(byproducts, pPrecision),
+		Precipitate:     p.precipitate,
+		Yield:           utils.RoundFloat(yield, pPrecision),
+	}, nil
+}
+
+type ppOutput struct {
+	Reaction        crOutput
+	Precipitant     string
+	Excess          float64
+	PrecipitantMass float64
+	Byproducts      []string
+	ByproductMasses []float64
+	Precipitate     string
+	Yield           float64
+}
+
+func (o ppOutput) String() string {
+	out := o.Reaction.String() + "\n" +
+		fmt.Sprintf("precipitant with %v%% excess: %s m = %v g\n", o.Excess*100, o.Precipitant, o.PrecipitantMass)
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	for i, byproduct := range o.Byproducts {
+		fmt.Fprintf(w, "by-product\t%s\tm = %v\tg\n", byproduct, o.ByproductMasses[i])
+	}
+
+	w.Flush()
+	return out + buf.String() + fmt.Sprintf("theoretical yield: %s m = %v g", o.Precipitate, o.Yield)
+}
@@ -0,0 +1,150 @@
+package chemreaction
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestIonicFormula(t *testing.T) {
+	tests := []struct {
+		cation   ion
+		anion    ion
+		expected string
+	}{
+		{ion{"Na", 1}, ion{"NO3", 1}, "NaNO3"},
+		{ion{"NH4", 1}, ion{"SO4", 2}, "(NH4)2SO4"},
+		{ion{"Fe", 3}, ion{"OH", 1}, "Fe(OH)3"},
+		{ion{"Fe", 3}, ion{"CO3", 2}, "Fe2(CO3)3"},
+		{ion{"H", 1}, ion{"Cl", 1}, "HCl"},
+	}
+	for _, tt := range tests {
+		if got := ionicFormula(tt.cation, tt.anion); got != tt.expected {
+			t.Errorf("ionicFormula(%v, %v) = %s, expected %s", tt.cation, tt.anion, got, tt.expected)
+		}
+	}
+}
+
+func TestPrecipitation(t *testing.T) {
+	tests := []struct {
+		name        string
+		precursors  []string
+		precipitant string
+		precipitate string
+		expected    string
+		byproducts  []string
+	}{
+		{
+			name:        "hydroxide",
+			precursors:  []string{"Ni(NO3)2"},
+			precipitant: "NaOH",
+			expected:    "Ni(NO3)2+2NaOH=Ni(OH)2+2NaNO3",
+			byproducts:  []string{"NaNO3"},
+		},
+		{
+			name:        "ammonia and hydrate",
+			precursors:  []string{"NiSO4*6H2O"},
+			precipitant: "NH4OH",
+			expected:    "NiSO4*6H2O+2NH4OH=Ni(OH)2+(NH4)2SO4+6H2O",
+			byproducts:  []string{"(NH4)2SO4"},
+		},
+		{
+			name:        "oxalate",
+			precursors:  []string{"Co(C2H3O2)2*4H2O"},
+			precipitant: "C2H2O4",
+			precipitate: "CoC2O4*2H2O",
+			expected:    "Co(C2H3O2)2*4H2O+C2H2O4=CoC2O4*2H2O+2HC2H3O2+2H2O",
+			byproducts:  []string{"HC2H3O2"},
+		},
+		{
+			name:        "co-precipitation",
+			precursors:  []string{"Ni(NO3)2*6H2O", "Co(NO3)2*6H2O", "Mn(NO3)2*4H2O"},
+			precipitant: "NaOH",
+			precipitate: "Ni0.8Co0.1Mn0.1(OH)2",
+			expected:    "0.8Ni(NO3)2*6H2O+0.1Co(NO3)2*6H2O+0.1Mn(NO3)2*4H2O+2NaOH=Ni0.8Co0.1Mn0.1(OH)2+2NaNO3+5.8H2O",
+			byproducts:  []string{"NaNO3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPrecipitation(tt.precursors, tt.precipitant, tt.precipitate, 0.1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reac, err := p.Reaction()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := reac.FinalReactionNorm()
+			if got != tt.expected {
+				t.Errorf("FinalReactionNorm() = %s, expected %s", got, tt.expected)
+			}
+			if !slices.Equal(p.Byproducts(), tt.byproducts) {
+				t.Errorf("Byproducts() = %v, expected %v", p.Byproducts(), tt.byproducts)
+			}
+		})
+	}
+}
+
+func TestPrecipitation_masses(t *testing.T) {
+	opts := ReacOptions{Rmode: Balance, Target: -2, TargerMass: 0.927074, Intify: true, Precision: 8, Tolerance: 1e-8}
+	p, err := NewPrecipitation([]string{"Ni(NO3)2"}, "NaOH", "", 0.2, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stoich, _ := p.StoichiometricPrecipitantMass()
+	mass, _ := p.PrecipitantMass()
+	if math.Abs(stoich-0.799936) > 1e-5 || math.Abs(mass-1.2*stoich) > 1e-6 {
+		t.Errorf("precipitant masses = %v, %v, expected 0.799936, %v", stoich, mass, 1.2*stoich)
+	}
+	yield, _ := p.Yield()
+	if math.Abs(yield-0.927074) > 1e-5 {
+		t.Errorf("Yield() = %v, expected 0.927074", yield)
+	}
+	byproducts, _ := p.ByproductMasses()
+	if len(byproducts) != 1 || math.Abs(byproducts[0]-1.699876) > 1e-5 {
+		t.Errorf("ByproductMasses() = %v, expected [1.699876]", byproducts)
+	}
+}
+
+func TestPrecipitation_spectators(t *testing.T) {
+	p, err := NewPrecipitation([]string{"NiSO4*6H2O"}, "NH4OH", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spectators, _ := p.Spectators()
+	if !slices.Equal(spectators, []string{"H2O"}) {
+		t.Errorf("Spectators() = %v, expected [H2O]", spectators)
+	}
+	byproducts, _ := p.ByproductMasses()
+	masses, _ := p.masses()
+	if len(byproducts) != 2 || byproducts[1] != masses[len(masses)-1] {
+		t.Errorf("ByproductMasses() = %v, expected (NH4)2SO4 and H2O", byproducts)
+	}
+	out, _ := p.Output()
+	if !slices.Equal(out.Byproducts, []string{"(NH4)2SO4", "H2O"}) {
+		t.Errorf("Output().Byproducts = %v, expected [(NH4)2SO4 H2O]", out.Byproducts)
+	}
+}
+
+func TestNewPrecipitation_invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		precursors  []string
+		precipitant string
+		precipitate string
+	}{
+		{"unknown precipitant", []string{"Ni(NO3)2"}, "Ca(OH)2", ""},
+		{"unknown anion", []string{"Ni3(PO4)2"}, "NaOH", ""},
+		{"no precipitate for co-precipitation", []string{"Ni(NO3)2", "Co(NO3)2"}, "NaOH", ""},
+		{"no metal", []string{"NH4NO3"}, "NaOH", ""},
+		{"fractional valence", []string{"Fe3(NO3)8"}, "NaOH", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPrecipitation(tt.precursors, tt.precipitant, tt.precipitate, 0); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -26,6 +26,7 @@ type ChemicalReaction struct {
 	finalReac      *string
 	finalReacNorm  *string
 	masses         *[]float64
+	auxiliary      []AuxiliaryReagent
 }
 
 type Mode int
@@ -50,8 +51,12 @@ type ReacOptions struct {
 }
 
 func NewChemicalReaction(reaction string, options ...ReacOptions) (*ChemicalReaction, error) {
+	return newChemicalReaction(reaction, false, options...)
+}
+
+func newChemicalReaction(reaction string, singleCompounds bool, options ...ReacOptions) (*ChemicalReaction, error) {
 	newReaction := strings.Replace(reaction, " ", "", -1)
-	validator := reactionValidator{reaction: newReaction}
+	validator := reactionValidator{reaction: newReaction, singleCompounds: singleCompounds}
 	decomp, err := validator.validate()
 	if err != nil {
 		return nil, err
@@ -355,6 +360,17 @@ func (r *ChemicalReaction) Output(printPrecision ...uint) (crOutput, error) {
 	if err != nil {
 		return crOutput{}, err
 	}
+	auxMasses, err := r.AuxiliaryMasses()
+	if err != nil {
+		return crOutput{}, err
+	}
+	auxFormulas := make([]string, len(r.auxiliary))
+	auxMolars := make([]float64, len(r.auxiliary))
+	for i, reagent := range r.auxiliary {
+		f, _ := chemformula.NewChemicalFormula(reagent.Formula)
+		auxFormulas[i] = f.Formula()
+		auxMolars[i] = f.MolarMass()
+	}
 
 	crO := crOutput{
 		Reaction:          r.reaction,
@@ -370,6 +386,9 @@ func (r *ChemicalReaction) Output(printPrecision ...uint) (crOutput, error) {
 		MolarMasses:       utils.RoundFloatS(mMasses, pPrecision),
 		Target:            r.decomposer.compounds[target],
 		Masses:            utils.RoundFloatS(mass, pPrecision),
+		Auxiliary:         auxFormulas,
+		AuxMolarMasses:    utils.RoundFloatS(auxMolars, pPrecision),
+		AuxMasses:         utils.RoundFloatS(auxMasses, pPrecision),
 	}
 	return crO, nil
 }
@@ -388,6 +407,9 @@ type crOutput struct {
 	MolarMasses       []float64
 	Target            string
 	Masses            []float64
+	Auxiliary         []string
+	AuxMolarMasses    []float64
+	AuxMasses         []float64
 }
 
 func (o crOutput) String() string {
@@ -412,6 +434,10 @@ func (o crOutput) String() string {
 		fmt.Fprintf(w, "%s\tM = %v\tg/mol\tm = %v\tg\n",
 			comp, o.MolarMasses[i], o.Masses[i])
 	}
+	for i, comp := range o.Auxiliary {
+		fmt.Fprintf(w, "%s\tM = %v\tg/mol\tm = %v\tg\t(auxiliary)\n",
+			comp, o.AuxMolarMasses[i], o.AuxMasses[i])
+	}
 
 	w.Flush()
 	return out + strings.TrimSuffix(buf.String(), "\n")
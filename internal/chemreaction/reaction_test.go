@@ -3,6 +3,8 @@ package chemreaction
 import (
 	"slices"
 	"testing"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
 )
 
 func TestChemicalReacutionOutput(t *testing.T) {
@@ -166,3 +168,20 @@ func TestChemicalReaction_setCoefficientsRight(t *testing.T) {
 			reac_coefs.Result)
 	}
 }
+
+func TestChemicalReaction_mixtureReactant(t *testing.T) {
+	limestone, _ := chemformula.NewChemicalMixture(
+		[]chemformula.Atom{{Label: "CaCO3", Amount: 98}, {Label: "MgCO3", Amount: 2}},
+		chemformula.MassBasis)
+	reac, err := NewChemicalReaction(limestone.Formula() + "=CaO+MgO+CO2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	molars, _ := reac.MolarMasses()
+	if molars[0] != limestone.MolarMass() {
+		t.Errorf("molar mass of mixture expected %v, got %v", limestone.MolarMass(), molars[0])
+	}
+	if !reac.IsBalanced() {
+		t.Errorf("reaction %s should be balanced", reac.reaction)
+	}
+}
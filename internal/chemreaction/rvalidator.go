@@ -6,7 +6,8 @@ import (
 )
 
 type reactionValidator struct {
-	reaction string
+	reaction        string
+	singleCompounds bool
 }
 
 func (v reactionValidator) emptyReaction() bool {
@@ -22,7 +23,7 @@ func (v reactionValidator) noRPSeparator(decomp reactionDecomposer) bool {
 }
 
 func (v reactionValidator) noReacSeparator() bool {
-	return !strings.Contains(v.reaction, reactionRegexes.reactantSeparator)
+	return !v.singleCompounds && !strings.Contains(v.reaction, reactionRegexes.reactantSeparator)
 }
 
 func (v reactionValidator) validate() (*reactionDecomposer, error) {
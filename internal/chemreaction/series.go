@@ -0,0 +1,341 @@
+package chemreaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+func SeriesRange(start, stop, step float64) []float64 {
+	if step <= 0 || stop < start {
+		return nil
+	}
+	n := int(math.Floor((stop-start)/step+1e-9)) + 1
+	values := make([]float64, n)
+	for i := range n {
+		values[i] = utils.RoundFloat(start+float64(i)*step, 10)
+	}
+	return values
+}
+
+func compoundElements(compound string) ([]string, error) {
+	form, err := chemformula.NewChemicalFormula(compound)
+	if err != nil {
+		return nil, err
+	}
+	elements := []string{}
+	for _, atom := range form.ParsedFormula() {
+		if atom.Amount > 0 {
+			elements = append(elements, atom.Label)
+		}
+	}
+	return elements, nil
+}
+
+func pruneReaction(reaction string, target int) (string, []bool, int, error) {
+	decomp, err := newReactionDecomposer(reaction)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	elements := make([][]string, len(decomp.compounds))
+	for i, compound := range decomp.compounds {
+		elements[i], err = compoundElements(compound)
+		if err != nil {
+			return "", nil, 0, err
+		}
+	}
+
+	kept := make([]bool, len(decomp.compounds))
+	for i := range kept {
+		kept[i] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		sides := [2][]string{}
+		for i, els := range elements {
+			if kept[i] {
+				side := 0
+				if i >= decomp.separatorPos {
+					side = 1
+				}
+				sides[side] = append(sides[side], els...)
+			}
+		}
+		for i, els := range elements {
+			other := 1
+			if i >= decomp.separatorPos {
+				other = 0
+			}
+			for _, el := range els {
+				if kept[i] && !slices.Contains(sides[other], el) {
+					kept[i] = false
+					changed = true
+				}
+			}
+		}
+	}
+
+	absTarget := target + decomp.separatorPos
+	if absTarget < 0 || absTarget >= len(kept) {
+		return "", nil, 0, fmt.Errorf("the target integer %d should be in range %d : %d",
+			target, -decomp.separatorPos, len(decomp.products)-1)
+	}
+	if !kept[absTarget] {
+		return "", nil, 0, fmt.Errorf("target compound %s is absent in the reaction", decomp.compounds[absTarget])
+	}
+
+	sides := [2][]string{}
+	newTarget := 0
+	for i, compound := range decomp.compounds {
+		if !kept[i] {
+			continue
+		}
+		if decomp.initCoefs[i] != 1 {
+			compound = formatFloat(decomp.initCoefs[i]) + compound
+		}
+		if i < decomp.separatorPos {
+			sides[0] = append(sides[0], compound)
+			if i >= absTarget {
+				newTarget--
+			}
+		} else {
+			sides[1] = append(sides[1], compound)
+			if i < absTarget {
+				newTarget++
+			}
+		}
+	}
+	if len(sides[0]) == 0 || len(sides[1]) == 0 {
+		return "", nil, 0, fmt.Errorf("no compounds left in one part of the reaction '%s'", reaction)
+	}
+
+	pruned := strings.Join(sides[0], reactionRegexes.reactantSeparator) +
+		decomp.separator +
+		strings.Join(sides[1], reactionRegexes.reactantSeparator)
+	return pruned, kept, newTarget, nil
+}
+
+type member struct {
+	reaction    string
+	formulas    []string
+	masses      []float64
+	molarMasses []float64
+}
+
+func balanceMember(reaction string, opts ReacOptions) (member, error) {
+	pruned, kept, target, err := pruneReaction(reaction, opts.Target)
+	if err != nil {
+		return member{}, err
+	}
+	opts.Target = target
+	reac, err := NewChemicalReaction(pruned, opts)
+	if err != nil {
+		return member{}, err
+	}
+	masses, err := reac.Masses()
+	if err != nil {
+		return member{}, err
+	}
+	molarMasses, err := reac.MolarMasses()
+	if err != nil {
+		return member{}, err
+	}
+	final, err := reac.FinalReactionNorm()
+	if err != nil {
+		return member{}, err
+	}
+
+	m := member{
+		reaction:    final,
+		formulas:    make([]string, len(kept)),
+		masses:      make([]float64, len(kept)),
+		molarMasses: make([]float64, len(kept)),
+	}
+	j := 0
+	for i := range kept {
+		if kept[i] {
+			m.formulas[i] = reac.decomposer.compounds[j]
+			m.masses[i] = masses[j]
+			m.molarMasses[i] = molarMasses[j]
+			j++
+		}
+	}
+	return m, nil
+}
+
+type SeriesRow struct {
+	Value    float64
+	Reaction string
+	Formulas []string
+	Masses   []float64
+	Error    string
+}
+
+type ReactionSeries struct {
+	template  *ReactionTemplate
+	variable  string
+	values    []float64
+	fixed     map[string]float64
+	reacOpts  ReacOptions
+	compounds []string
+	rows      *[]SeriesRow
+}
+
+func NewReactionSeries(template *ReactionTemplate, variable string, values []float64, options ...ReacOptions) (*ReactionSeries, error) {
+	if !slices.Contains(template.variables, variable) {
+		return nil, fmt.Errorf("there is no variable '%s' in the template '%s'", variable, template.template)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("empty list of values for variable '%s'", variable)
+	}
+
+	var reacOpt ReacOptions
+	if options == nil {
+		reacOpt = ReacOptions{
+			Rmode:      Balance,
+			Target:     0,
+			TargerMass: 1.0,
+			Intify:     true,
+			Precision:  8,
+			Tolerance:  1e-8,
+		}
+	} else {
+		reacOpt = options[0]
+	}
+
+	separator := extractSeparator(template.template)
+	if separator == "" {
+		return nil, fmt.Errorf("no separator between reactants and products: %s in the reaction '%s'",
+			reactionRegexes.reactionSeparators, template.template)
+	}
+	sides := strings.SplitN(template.template, separator, 2)
+	compounds := append(
+		strings.Split(sides[0], reactionRegexes.reactantSeparator),
+		strings.Split(sides[1], reactionRegexes.reactantSeparator)...)
+
+	return &ReactionSeries{
+		template:  template,
+		variable:  variable,
+		values:    values,
+		fixed:     map[string]float64{},
+		reacOpts:  reacOpt,
+		compounds: compounds,
+	}, nil
+}
+
+func (s *ReactionSeries) SetFixed(values map[string]float64) {
+	s.fixed = values
+	s.rows = nil
+}
+
+func (s *ReactionSeries) Compounds() []string {
+	return s.compounds
+}
+
+func (s *ReactionSeries) row(value float64) SeriesRow {
+	row := SeriesRow{Value: value}
+
+	values := map[string]float64{s.variable: value}
+	for k, v := range s.fixed {
+		if k != s.variable {
+			values[k] = v
+		}
+	}
+	reaction, err := s.template.Substitute(values)
+	if err != nil {
+		row.Error = err.Error()
+		return row
+	}
+	m, err := balanceMember(reaction, s.reacOpts)
+	if err != nil {
+		row.Error = err.Error()
+		return row
+	}
+	row.Reaction = m.reaction
+	row.Formulas = m.formulas
+	row.Masses = m.masses
+	return row
+}
+
+func (s *ReactionSeries) Rows() []SeriesRow {
+	if s.rows == nil {
+		rows := make([]SeriesRow, len(s.values))
+		for i, value := range s.values {
+			rows[i] = s.row(value)
+		}
+		s.rows = &rows
+	}
+	return *s.rows
+}
+
+func (s *ReactionSeries) Output(printPrecision ...uint) seriesOutput {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	rows := s.Rows()
+	pRows := make([]SeriesRow, len(rows))
+	for i, row := range rows {
+		pRows[i] = row
+		pRows[i].Masses = utils.RoundFloatS(row.Masses, pPrecision)
+	}
+
+	return seriesOutput{
+		Template:   s.template.template,
+		Variable:   s.variable,
+		TargetMass: s.reacOpts.TargerMass,
+		Compounds:  s.compounds,
+		Rows:       pRows,
+	}
+}
+
+type seriesOutput struct {
+	Template   string
+	Variable   string
+	TargetMass float64
+	Compounds  []string
+	Rows       []SeriesRow
+}
+
+func (o seriesOutput) table() table {
+	t := table{header: append(append([]string{o.Variable}, o.Compounds...), "reaction")}
+	for _, row := range o.Rows {
+		line := []string{formatFloat(row.Value)}
+		for i := range o.Compounds {
+			if row.Error != "" {
+				line = append(line, "-")
+			} else {
+				line = append(line, formatFloat(row.Masses[i]))
+			}
+		}
+		if row.Error != "" {
+			line = append(line, "error: "+row.Error)
+		} else {
+			line = append(line, row.Reaction)
+		}
+		t.rows = append(t.rows, line)
+	}
+	return t
+}
+
+func (o seriesOutput) String() string {
+	return fmt.Sprintln("template:", o.Template) +
+		fmt.Sprintln("target mass:", o.TargetMass) +
+		o.table().String()
+}
+
+func (o seriesOutput) CSV() (string, error) {
+	return o.table().CSV()
+}
+
+func (o seriesOutput) JSON() ([]byte, error) {
+	return json.Marshal(o)
+}
@@ -0,0 +1,97 @@
+package chemreaction
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSeriesRange(t *testing.T) {
+	tests := []struct {
+		start, stop, step float64
+		expected          []float64
+	}{
+		{0, 0.5, 0.1, []float64{0, 0.1, 0.2, 0.3, 0.4, 0.5}},
+		{0, 0.1, 0.05, []float64{0, 0.05, 0.1}},
+		{1, 0, 0.1, nil},
+		{0, 1, 0, nil},
+	}
+	for _, tt := range tests {
+		got := SeriesRange(tt.start, tt.stop, tt.step)
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("SeriesRange(%v, %v, %v) = %v, expected %v", tt.start, tt.stop, tt.step, got, tt.expected)
+		}
+	}
+}
+
+func TestPruneReaction(t *testing.T) {
+	tests := []struct {
+		reaction       string
+		target         int
+		expected       string
+		expectedKept   []bool
+		expectedTarget int
+	}{
+		{"BaCO3+SrCO3+TiO2=BaTiO3+CO2", 0, "BaCO3+TiO2=BaTiO3+CO2", []bool{true, false, true, true, true}, 0},
+		{"BaCO3+SrCO3+TiO2=SrTiO3+CO2", -1, "SrCO3+TiO2=SrTiO3+CO2", []bool{false, true, true, true, true}, -1},
+		{"Li2CO3+Fe2O3+Mn2O3=LiFePO4+CO2", 0, "", nil, 0},
+	}
+	for _, tt := range tests {
+		got, kept, target, err := pruneReaction(tt.reaction, tt.target)
+		if tt.expected == "" {
+			if err == nil {
+				t.Errorf("pruneReaction(%s) expected error", tt.reaction)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.expected || !slices.Equal(kept, tt.expectedKept) || target != tt.expectedTarget {
+			t.Errorf("pruneReaction(%s) = %s, %v, %d, expected %s, %v, %d",
+				tt.reaction, got, kept, target, tt.expected, tt.expectedKept, tt.expectedTarget)
+		}
+	}
+}
+
+func TestReactionSeries_Rows(t *testing.T) {
+	tmpl, _ := NewReactionTemplate("BaCO3+SrCO3+TiO2=Ba1-xSrxTiO3+CO2")
+	opts := ReacOptions{Rmode: Balance, Target: 0, TargerMass: 5, Intify: true, Precision: 8, Tolerance: 1e-8}
+	series, err := NewReactionSeries(tmpl, "x", []float64{0, 0.25, 1.2}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := series.Rows()
+	if len(rows) != 3 {
+		t.Fatalf("len(Rows()) = %d, expected 3", len(rows))
+	}
+
+	if rows[0].Reaction != "BaCO3+TiO2=BaTiO3+CO2" || rows[0].Masses[1] != 0 || rows[0].Masses[3] != 5 {
+		t.Errorf("Rows()[0] = %v", rows[0])
+	}
+	expected := "0.75BaCO3+0.25SrCO3+TiO2=Ba0.75Sr0.25TiO3+CO2"
+	if rows[1].Reaction != expected || rows[1].Formulas[3] != "Ba0.75Sr0.25TiO3" {
+		t.Errorf("Rows()[1].Reaction = %s, expected %s", rows[1].Reaction, expected)
+	}
+	if rows[2].Error == "" {
+		t.Errorf("Rows()[2] expected error for negative subscript")
+	}
+
+	csv, err := series.Output().CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(csv, "x,BaCO3,SrCO3,TiO2,Ba1-xSrxTiO3,CO2,reaction\n0,4.2312,0,1.7124,5,0.9436,") {
+		t.Errorf("CSV() = %s", csv)
+	}
+}
+
+func TestNewReactionSeries_invalid(t *testing.T) {
+	tmpl, _ := NewReactionTemplate("BaCO3+SrCO3+TiO2=Ba1-xSrxTiO3+CO2")
+	if _, err := NewReactionSeries(tmpl, "y", []float64{0.1}); err == nil {
+		t.Error("expected error for unknown variable")
+	}
+	if _, err := NewReactionSeries(tmpl, "x", nil); err == nil {
+		t.Error("expected error for empty values")
+	}
+}
@@ -0,0 +1,413 @@
+package chemreaction
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+type Criterion int
+
+const (
+	FewestPrecursors Criterion = iota
+	LeastGas
+	LowestCost
+	PreferredAnions
+)
+
+func (c Criterion) String() string {
+	return [...]string{"FewestPrecursors", "LeastGas", "LowestCost", "PreferredAnions"}[c]
+}
+
+var anionFamilyElements = []string{"O", "H", "C", "N"}
+
+var anionPatterns = []struct {
+	family  string
+	formula string
+}{
+	{"oxalate", "C2O4"},
+	{"carbonate", "CO3"},
+	{"acetate", "C2H3O2"},
+	{"nitrate", "NO3"},
+	{"hydroxide", "OH"},
+}
+
+func anionCount(part map[string]float64, anion []chemformula.Atom) (float64, bool) {
+	n := 0.0
+	for _, atom := range anion {
+		if atom.Label != "O" && atom.Label != "H" {
+			n = part[atom.Label] / atom.Amount
+			break
+		}
+	}
+	if n == 0 {
+		n = 2*part["O"] - part["H"]
+	}
+	if n <= 1e-9 {
+		return 0, false
+	}
+	rest := maps.Clone(part)
+	for _, atom := range anion {
+		rest[atom.Label] -= n * atom.Amount
+	}
+	for label, amount := range rest {
+		if label != "O" && label != "H" && math.Abs(amount) > 1e-9 {
+			return 0, false
+		}
+	}
+	water := rest["O"]
+	return n, water > -1e-9 && math.Abs(rest["H"]-2*water) <= 1e-9
+}
+
+var defaultAnionPreference = []string{
+	"carbonate", "oxide", "hydroxide", "oxalate", "acetate", "organic", "nitrate", "metal",
+}
+
+type Suggestion struct {
+	Precursors    []string
+	Families      []string
+	Compounds     []string
+	Reaction      string
+	Masses        []float64
+	Gas           float64
+	Cost          float64
+	MissingPrices []string
+	reaction      *ChemicalReaction
+}
+
+func (s Suggestion) ChemicalReaction() *ChemicalReaction {
+	return s.reaction
+}
+
+type PrecursorSuggester struct {
+	target     string
+	keys       []string
+	candidates []string
+	reacOpts   ReacOptions
+	prices     map[string]float64
+	preference []string
+	criteria   []Criterion
+	generated  *[]Suggestion
+}
+
+func NewPrecursorSuggester(target string, inventory []string, options ...ReacOptions) (*PrecursorSuggester, error) {
+	newTarget := strings.Replace(target, " ", "", -1)
+	f, err := chemformula.NewChemicalFormula(newTarget)
+	if err != nil {
+		return nil, err
+	}
+	elements := elementSet(f.ParsedFormula())
+	keys := []string{}
+	for _, element := range elements {
+		if !slices.Contains(anionFamilyElements, element) {
+			keys = append(keys, element)
+		}
+	}
+	if len(keys) == 0 {
+		keys = elements
+	}
+
+	candidates := []string{}
+	for _, precursor := range inventory {
+		precursor = strings.Replace(precursor, " ", "", -1)
+		p, err := chemformula.NewChemicalFormula(precursor)
+		if err != nil {
+			return nil, err
+		}
+		if precursor == newTarget || slices.Contains(candidates, precursor) {
+			continue
+		}
+		useful := false
+		foreign := false
+		for _, element := range elementSet(p.ParsedFormula()) {
+			if slices.Contains(keys, element) {
+				useful = true
+			} else if !slices.Contains(anionFamilyElements, element) {
+				foreign = true
+			}
+		}
+		if useful && !foreign {
+			candidates = append(candidates, precursor)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("there are no precursors of %v for %s in the inventory", keys, newTarget)
+	}
+
+	var reacOpt ReacOptions
+	if options == nil {
+		reacOpt = ReacOptions{
+			Rmode:      Balance,
+			Target:     0,
+			TargerMass: 1.0,
+			Intify:     true,
+			Precision:  8,
+			Tolerance:  1e-8,
+		}
+	} else {
+		reacOpt = options[0]
+	}
+	reacOpt.Rmode = Balance
+	reacOpt.Target = 0
+
+	return &PrecursorSuggester{
+		target:     newTarget,
+		keys:       keys,
+		candidates: candidates,
+		reacOpts:   reacOpt,
+		prices:     map[string]float64{},
+		preference: defaultAnionPreference,
+		criteria:   []Criterion{FewestPrecursors, LeastGas},
+	}, nil
+}
+
+func (s *PrecursorSuggester) Candidates() []string {
+	return s.candidates
+}
+
+func (s *PrecursorSuggester) SetPrices(prices map[string]float64) error {
+	newPrices := map[string]float64{}
+	for formula, price := range prices {
+		if price < 0 {
+			return fmt.Errorf("negative price %v of %s", price, formula)
+		}
+		newPrices[strings.Replace(formula, " ", "", -1)] = price
+	}
+	s.prices = newPrices
+	s.generated = nil
+	return nil
+}
+
+func (s *PrecursorSuggester) SetAnionPreference(families []string) {
+	s.preference = families
+}
+
+func (s *PrecursorSuggester) SetCriteria(criteria ...Criterion) error {
+	for _, c := range criteria {
+		if c < FewestPrecursors || c > PreferredAnions {
+			return fmt.Errorf("no such criterion %d", c)
+		}
+	}
+	s.criteria = criteria
+	return nil
+}
+
+func (s *PrecursorSuggester) family(precursor string) string {
+	part := map[string]float64{}
+	for _, atom := range parseFormula(precursor) {
+		if !slices.Contains(s.keys, atom.Label) {
+			part[atom.Label] += atom.Amount
+		}
+	}
+	for _, anion := range anionPatterns {
+		if _, ok := anionCount(part, parseFormula(anion.formula)); ok {
+			return anion.family
+		}
+	}
+	if part["C"] > 0 && part["H"] > 0 {
+		return "organic"
+	}
+	for label := range part {
+		if label != "O" {
+			return "other"
+		}
+	}
+	if part["O"] > 0 {
+		return "oxide"
+	}
+	return "metal"
+}
+
+func (s *PrecursorSuggester) anionRank(suggestion Suggestion) int {
+	rank := 0
+	for _, family := range suggestion.Families {
+		i := slices.Index(s.preference, family)
+		if i < 0 {
+			i = len(s.preference)
+		}
+		rank = max(rank, i)
+	}
+	return rank
+}
+
+func (s *PrecursorSuggester) covers(set []int) bool {
+	contributions := make([][]string, len(set))
+	covered := map[string]int{}
+	for i, j := range set {
+		for _, element := range elementSet(parseFormula(s.candidates[j])) {
+			if slices.Contains(s.keys, element) {
+				contributions[i] = append(contributions[i], element)
+				covered[element]++
+			}
+		}
+	}
+	if len(covered) != len(s.keys) {
+		return false
+	}
+	for _, contribution := range contributions {
+		unique := false
+		for _, element := range contribution {
+			if covered[element] == 1 {
+				unique = true
+			}
+		}
+		if !unique {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *PrecursorSuggester) suggestion(precursors []string) (Suggestion, error) {
+	p, err := newPrecursorReaction(s.target, precursors, s.reacOpts)
+	if err != nil {
+		return Suggestion{}, err
+	}
+
+	suggestion := Suggestion{
+		Precursors: precursors,
+		Compounds:  p.reaction.decomposer.compounds,
+		Reaction:   p.final,
+		Masses:     p.masses,
+		Gas:        p.gas,
+		reaction:   p.reaction,
+	}
+	for _, precursor := range precursors {
+		suggestion.Families = append(suggestion.Families, s.family(precursor))
+	}
+	suggestion.Cost, suggestion.MissingPrices = p.cost(precursors, s.prices)
+	return suggestion, nil
+}
+
+func (s *PrecursorSuggester) generate() []Suggestion {
+	if s.generated == nil {
+		suggestions := []Suggestion{}
+		for size := 1; size <= min(len(s.keys), len(s.candidates)); size++ {
+			for _, set := range combinations(len(s.candidates), size) {
+				if !s.covers(set) {
+					continue
+				}
+				precursors := make([]string, len(set))
+				for i, j := range set {
+					precursors[i] = s.candidates[j]
+				}
+				suggestion, err := s.suggestion(precursors)
+				if err == nil {
+					suggestions = append(suggestions, suggestion)
+				}
+			}
+		}
+		s.generated = &suggestions
+	}
+	return *s.generated
+}
+
+func (s *PrecursorSuggester) compare(a Suggestion, b Suggestion) int {
+	for _, criterion := range s.criteria {
+		var c int
+		switch criterion {
+		case FewestPrecursors:
+			c = cmp.Compare(len(a.Precursors), len(b.Precursors))
+		case LeastGas:
+			c = cmp.Compare(a.Gas, b.Gas)
+		case LowestCost:
+			c = cmp.Compare(len(a.MissingPrices), len(b.MissingPrices))
+			if c == 0 {
+				c = cmp.Compare(a.Cost, b.Cost)
+			}
+		case PreferredAnions:
+			c = cmp.Compare(s.anionRank(a), s.anionRank(b))
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func (s *PrecursorSuggester) Suggestions() ([]Suggestion, error) {
+	suggestions := slices.Clone(s.generate())
+	if len(suggestions) == 0 {
+		return nil, fmt.Errorf("can't generate any reaction for %s from %v", s.target, s.candidates)
+	}
+	slices.SortStableFunc(suggestions, s.compare)
+	return suggestions, nil
+}
+
+func (s *PrecursorSuggester) Output(printPrecision ...uint) (sgOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	suggestions, err := s.Suggestions()
+	if err != nil {
+		return sgOutput{}, err
+	}
+	for i, suggestion := range suggestions {
+		suggestions[i].Masses = utils.RoundFloatS(suggestion.Masses, pPrecision)
+		suggestions[i].Gas = utils.RoundFloat(suggestion.Gas, pPrecision)
+		suggestions[i].Cost = utils.RoundFloat(suggestion.Cost, pPrecision)
+	}
+	criteria := make([]string, len(s.criteria))
+	for i, c := range s.criteria {
+		criteria[i] = c.String()
+	}
+
+	return sgOutput{
+		Target:      s.target,
+		TargetMass:  s.reacOpts.TargerMass,
+		Criteria:    criteria,
+		Suggestions: suggestions,
+	}, nil
+}
+
+type sgOutput struct {
+	Target      string
+	TargetMass  float64
+	Criteria    []string
+	Suggestions []Suggestion
+}
+
+func (o sgOutput) table() table {
+	t := table{header: []string{"rank", "precursors", "families", "gas, g", "cost", "reaction"}}
+	for i, s := range o.Suggestions {
+		cost := formatFloat(s.Cost)
+		if len(s.MissingPrices) > 0 {
+			cost = "-"
+		}
+		t.rows = append(t.rows, []string{
+			fmt.Sprint(i + 1),
+			strings.Join(s.Precursors, ", "),
+			strings.Join(s.Families, ", "),
+			formatFloat(s.Gas),
+			cost,
+			s.Reaction,
+		})
+	}
+	return t
+}
+
+func (o sgOutput) String() string {
+	return fmt.Sprintln("target:", o.Target) +
+		fmt.Sprintln("target mass:", o.TargetMass) +
+		fmt.Sprintln("criteria:", o.Criteria) +
+		o.table().String()
+}
+
+func (o sgOutput) CSV() (string, error) {
+	return o.table().CSV()
+}
+
+func (o sgOutput) JSON() ([]byte, error) {
+	return json.Marshal(o)
+}
@@ -0,0 +1,155 @@
+package chemreaction
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var testInventory = []string{"BaCO3", "Ba(NO3)2", "BaO", "TiO2", "Ti(OC3H7)4", "SrCO3", "BaCl2"}
+
+func TestPrecursorSuggester(t *testing.T) {
+	s, err := NewPrecursorSuggester("BaTiO3", testInventory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"BaCO3", "Ba(NO3)2", "BaO", "TiO2", "Ti(OC3H7)4"}
+	if !slices.Equal(s.Candidates(), expected) {
+		t.Errorf("Candidates() = %v, expected %v", s.Candidates(), expected)
+	}
+	err = s.SetPrices(map[string]float64{"BaCO3": 0.1, "Ba(NO3)2": 0.08, "BaO": 0.5, "TiO2": 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		criteria []Criterion
+		first    []string
+		last     []string
+	}{
+		{[]Criterion{FewestPrecursors, LeastGas}, []string{"BaO", "TiO2"}, []string{"BaCO3", "Ti(OC3H7)4"}},
+		{[]Criterion{LowestCost}, []string{"BaCO3", "TiO2"}, []string{"BaO", "Ti(OC3H7)4"}},
+		{[]Criterion{PreferredAnions, LeastGas}, []string{"BaO", "TiO2"}, []string{"Ba(NO3)2", "TiO2"}},
+	}
+	for _, tt := range tests {
+		err := s.SetCriteria(tt.criteria...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		suggestions, err := s.Suggestions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(suggestions) != 5 {
+			t.Fatalf("expected 5 suggestions, got %d", len(suggestions))
+		}
+		first, last := suggestions[0].Precursors, suggestions[len(suggestions)-1].Precursors
+		if !slices.Equal(first, tt.first) || !slices.Equal(last, tt.last) {
+			t.Errorf("%v: first %v and last %v, expected %v and %v", tt.criteria, first, last, tt.first, tt.last)
+		}
+		for _, suggestion := range suggestions {
+			if !suggestion.ChemicalReaction().IsBalanced() {
+				t.Errorf("reaction %s is not balanced", suggestion.Reaction)
+			}
+		}
+	}
+
+	suggestions, _ := s.Suggestions()
+	for _, suggestion := range suggestions {
+		if slices.Equal(suggestion.Precursors, []string{"BaCO3", "TiO2"}) {
+			if suggestion.Reaction != "BaCO3+TiO2=BaTiO3+CO2" {
+				t.Errorf("unexpected reaction %s", suggestion.Reaction)
+			}
+			if !slices.Equal(suggestion.Families, []string{"carbonate", "oxide"}) {
+				t.Errorf("unexpected families %v", suggestion.Families)
+			}
+		}
+	}
+}
+
+func TestPrecursorFamily(t *testing.T) {
+	s, err := NewPrecursorSuggester("BaTiO3", testInventory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		precursor string
+		expected  string
+	}{
+		{"BaCO3", "carbonate"},
+		{"BaC2O4", "oxalate"},
+		{"Ba(C2H3O2)2", "acetate"},
+		{"Ba(CH3COO)2", "acetate"},
+		{"Ba(NO3)2", "nitrate"},
+		{"BaSO4", "other"},
+		{"Ba(OH)2*8H2O", "hydroxide"},
+		{"BaCl2*2H2O", "other"},
+		{"Ba(ClO4)2", "other"},
+		{"Ti(OCH2CH2OH)4", "organic"},
+		{"Ti(OC3H7)4", "organic"},
+		{"TiO2", "oxide"},
+		{"Ti", "metal"},
+	}
+	for _, tt := range tests {
+		if got := s.family(tt.precursor); got != tt.expected {
+			t.Errorf("family(%s) = %s, expected %s", tt.precursor, got, tt.expected)
+		}
+	}
+}
+
+func TestPrecursorSuggesterOutput(t *testing.T) {
+	s, err := NewPrecursorSuggester("BaTiO3", []string{"BaCO3", "TiO2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := s.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "target: BaTiO3\n" +
+		"target mass: 1\n" +
+		"criteria: [FewestPrecursors LeastGas]\n" +
+		"rank  precursors   families          gas, g  cost  reaction\n" +
+		"1     BaCO3, TiO2  carbonate, oxide  0.1887  -     BaCO3+TiO2=BaTiO3+CO2"
+	if out.String() != expected {
+		t.Errorf("Output() = \n%s\nexpected\n%s", out, expected)
+	}
+	csv, err := out.CSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(csv, "rank,precursors,families,\"gas, g\",cost,reaction\n") {
+		t.Errorf("unexpected CSV:\n%s", csv)
+	}
+	data, err := out.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPrecursorSuggesterErrors(t *testing.T) {
+	_, err := NewPrecursorSuggester("BaTiO3", []string{"SrCO3", "BaCl2"})
+	if err == nil {
+		t.Error("expected an error for the inventory without precursors")
+	}
+	s, err := NewPrecursorSuggester("BaTiO3", []string{"BaCO3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Suggestions()
+	if err == nil {
+		t.Error("expected an error without Ti precursors")
+	}
+	if s.SetPrices(map[string]float64{"BaCO3": -1}) == nil {
+		t.Error("expected an error for a negative price")
+	}
+	if s.SetCriteria(Criterion(10)) == nil {
+		t.Error("expected an error for an unknown criterion")
+	}
+}
@@ -0,0 +1,48 @@
+package chemreaction
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+)
+
+type ReactionTemplate struct {
+	template  string
+	variables []string
+}
+
+func NewReactionTemplate(template string) (*ReactionTemplate, error) {
+	newTemplate := strings.Replace(template, " ", "", -1)
+	if newTemplate == "" {
+		return nil, fmt.Errorf("empty reaction template")
+	}
+	vars, err := chemformula.TemplateVariables(newTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &ReactionTemplate{
+		template:  newTemplate,
+		variables: vars,
+	}, nil
+}
+
+func (t *ReactionTemplate) Template() string {
+	return t.template
+}
+
+func (t *ReactionTemplate) Variables() []string {
+	return t.variables
+}
+
+func (t *ReactionTemplate) Substitute(values map[string]float64) (string, error) {
+	return chemformula.SubstituteVariables(t.template, values)
+}
+
+func (t *ReactionTemplate) Evaluate(values map[string]float64, options ...ReacOptions) (*ChemicalReaction, error) {
+	reaction, err := t.Substitute(values)
+	if err != nil {
+		return nil, err
+	}
+	return NewChemicalReaction(reaction, options...)
+}
@@ -0,0 +1,37 @@
+package chemreaction
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReactionTemplate_Evaluate(t *testing.T) {
+	tmpl, err := NewReactionTemplate("BaCO3+SrCO3+TiO2=Ba1-xSrxTiO3+CO2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tmpl.Variables(), []string{"x"}) {
+		t.Errorf("Variables() = %v, expected [x]", tmpl.Variables())
+	}
+	reac, err := tmpl.Evaluate(map[string]float64{"x": 0.25})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reac.FinalReactionNorm()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "0.75BaCO3+0.25SrCO3+TiO2=Ba0.75Sr0.25TiO3+CO2"
+	if got != expected {
+		t.Errorf("FinalReactionNorm() = %s, expected %s", got, expected)
+	}
+}
+
+func TestReactionTemplate_noValue(t *testing.T) {
+	tmpl, _ := NewReactionTemplate("YBa2Cu3O7-d=YBa2Cu3O6+O2")
+	_, err := tmpl.Evaluate(map[string]float64{"x": 0.25})
+	expected := "No value for variable 'd'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Evaluate() error = %v, expected %s", err, expected)
+	}
+}
@@ -0,0 +1,385 @@
+package chemreaction
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/chemformula"
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+const standardTemperature = 298.15
+
+var thermoCSVHeader = []string{"formula", "phase", "hf", "s", "a", "b", "c", "d", "tm", "hm", "cpl"}
+
+type ThermoData struct {
+	Formula  string    `json:"formula"`
+	Phase    string    `json:"phase"`
+	Hf       float64   `json:"hf"`
+	S        float64   `json:"s"`
+	Cp       []float64 `json:"cp"`
+	Tm       float64   `json:"tm,omitempty"`
+	Hm       float64   `json:"hm,omitempty"`
+	CpLiquid []float64 `json:"cpl,omitempty"`
+}
+
+func cpCoef(cp []float64, i int) float64 {
+	if i < len(cp) {
+		return cp[i]
+	}
+	return 0
+}
+
+func cpValue(cp []float64, t float64) float64 {
+	return cpCoef(cp, 0) + cpCoef(cp, 1)*t + cpCoef(cp, 2)/(t*t) + cpCoef(cp, 3)*t*t
+}
+
+func cpEnthalpy(cp []float64, from float64, to float64) float64 {
+	primitive := func(t float64) float64 {
+		return cpCoef(cp, 0)*t + cpCoef(cp, 1)*t*t/2 - cpCoef(cp, 2)/t + cpCoef(cp, 3)*t*t*t/3
+	}
+	return (primitive(to) - primitive(from)) / 1000
+}
+
+func cpEntropy(cp []float64, from float64, to float64) float64 {
+	primitive := func(t float64) float64 {
+		return cpCoef(cp, 0)*math.Log(t) + cpCoef(cp, 1)*t - cpCoef(cp, 2)/(2*t*t) + cpCoef(cp, 3)*t*t/2
+	}
+	return primitive(to) - primitive(from)
+}
+
+func (d ThermoData) liquidCp() []float64 {
+	if len(d.CpLiquid) == 0 {
+		return d.Cp
+	}
+	return d.CpLiquid
+}
+
+func (d ThermoData) melts(temperature float64) bool {
+	return d.Tm > standardTemperature && temperature > d.Tm
+}
+
+func (d ThermoData) HeatCapacity(temperature float64) float64 {
+	if d.melts(temperature) {
+		return cpValue(d.liquidCp(), temperature)
+	}
+	return cpValue(d.Cp, temperature)
+}
+
+func (d ThermoData) Enthalpy(temperature float64) float64 {
+	if d.melts(temperature) {
+		return d.Hf + cpEnthalpy(d.Cp, standardTemperature, d.Tm) + d.Hm + cpEnthalpy(d.liquidCp(), d.Tm, temperature)
+	}
+	return d.Hf + cpEnthalpy(d.Cp, standardTemperature, temperature)
+}
+
+func (d ThermoData) Entropy(temperature float64) float64 {
+	if d.melts(temperature) {
+		return d.S + cpEntropy(d.Cp, standardTemperature, d.Tm) + d.Hm*1000/d.Tm + cpEntropy(d.liquidCp(), d.Tm, temperature)
+	}
+	return d.S + cpEntropy(d.Cp, standardTemperature, temperature)
+}
+
+func (d ThermoData) Gibbs(temperature float64) float64 {
+	return d.Enthalpy(temperature) - temperature*d.Entropy(temperature)/1000
+}
+
+type ThermoTable struct {
+	data []ThermoData
+}
+
+func thermoKey(formula string) string {
+	return strings.Replace(formula, " ", "", -1)
+}
+
+func NewThermoTable(data []ThermoData) (*ThermoTable, error) {
+	table := &ThermoTable{}
+	for _, entry := range data {
+		err := table.Add(entry)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+func ReadThermoJSON(r io.Reader) (*ThermoTable, error) {
+	var data []ThermoData
+	err := json.NewDecoder(r).Decode(&data)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode thermochemical data: %w", err)
+	}
+	return NewThermoTable(data)
+}
+
+func ReadThermoCSV(r io.Reader) (*ThermoTable, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("can't read thermochemical data: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty thermochemical data")
+	}
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range thermoCSVHeader[:4] {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("there is no column '%s' in thermochemical data", name)
+		}
+	}
+
+	data := []ThermoData{}
+	for line, record := range records[1:] {
+		entry := ThermoData{
+			Formula: strings.TrimSpace(record[columns["formula"]]),
+			Phase:   strings.TrimSpace(record[columns["phase"]]),
+		}
+		values := make([]float64, len(thermoCSVHeader)-2)
+		for i, name := range thermoCSVHeader[2:] {
+			col, ok := columns[name]
+			if !ok || strings.TrimSpace(record[col]) == "" {
+				continue
+			}
+			values[i], err = strconv.ParseFloat(strings.TrimSpace(record[col]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of '%s' in line %d: %w", name, line+2, err)
+			}
+		}
+		entry.Hf, entry.S, entry.Cp = values[0], values[1], values[2:6]
+		entry.Tm, entry.Hm = values[6], values[7]
+		if values[8] != 0 {
+			entry.CpLiquid = []float64{values[8]}
+		}
+		data = append(data, entry)
+	}
+	return NewThermoTable(data)
+}
+
+func LoadThermoTable(path string) (*ThermoTable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ReadThermoJSON(file)
+	case ".csv":
+		return ReadThermoCSV(file)
+	default:
+		return nil, fmt.Errorf("unknown thermochemical data format of %s", path)
+	}
+}
+
+func (t *ThermoTable) Add(entry ThermoData) error {
+	_, err := chemformula.NewChemicalFormula(entry.Formula)
+	if err != nil {
+		return err
+	}
+	if len(entry.Cp) > 4 || len(entry.CpLiquid) > 4 {
+		return fmt.Errorf("too many heat capacity coefficients for %s, expected at most 4", entry.Formula)
+	}
+	if entry.Tm < 0 || entry.Hm < 0 {
+		return fmt.Errorf("negative melting data for %s", entry.Formula)
+	}
+	for _, d := range t.data {
+		if thermoKey(d.Formula) == thermoKey(entry.Formula) && d.Phase == entry.Phase {
+			return fmt.Errorf("duplicate thermochemical data for %s (%s)", entry.Formula, entry.Phase)
+		}
+	}
+	t.data = append(t.data, entry)
+	return nil
+}
+
+func (t *ThermoTable) Data() []ThermoData {
+	return t.data
+}
+
+func (t *ThermoTable) Phases(formula string) []ThermoData {
+	found := []ThermoData{}
+	for _, d := range t.data {
+		if thermoKey(d.Formula) == thermoKey(formula) {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+func (t *ThermoTable) Lookup(formula string, phase string) (ThermoData, error) {
+	found := []ThermoData{}
+	for _, d := range t.data {
+		if thermoKey(d.Formula) == thermoKey(formula) && (phase == "" || d.Phase == phase) {
+			found = append(found, d)
+		}
+	}
+	switch len(found) {
+	case 0:
+		if phase == "" {
+			return ThermoData{}, fmt.Errorf("there is no thermochemical data for %s", formula)
+		}
+		return ThermoData{}, fmt.Errorf("there is no thermochemical data for %s (%s)", formula, phase)
+	case 1:
+		return found[0], nil
+	default:
+		return ThermoData{}, fmt.Errorf("there are several phases of %s, the phase should be specified", formula)
+	}
+}
+
+type ReactionThermo struct {
+	reaction  *ChemicalReaction
+	data      []ThermoData
+	coefs     []float64
+	precision uint
+}
+
+func NewReactionThermo(reaction *ChemicalReaction, table *ThermoTable, phases map[string]string) (*ReactionThermo, error) {
+	coefs, err := reaction.Coefficients()
+	if err != nil {
+		return nil, err
+	}
+	if !reaction.IsBalanced() {
+		return nil, fmt.Errorf("reaction %s is not balanced", reaction.reaction)
+	}
+
+	data := make([]ThermoData, len(reaction.decomposer.compounds))
+	signed := make([]float64, len(data))
+	for i, compound := range reaction.decomposer.compounds {
+		data[i], err = table.Lookup(compound, phases[compound])
+		if err != nil {
+			return nil, err
+		}
+		signed[i] = coefs.Result[i]
+		if i < reaction.decomposer.separatorPos {
+			signed[i] = -signed[i]
+		}
+	}
+
+	return &ReactionThermo{
+		reaction:  reaction,
+		data:      data,
+		coefs:     signed,
+		precision: reaction.reacOpts.Precision,
+	}, nil
+}
+
+func (rt *ReactionThermo) Reaction() *ChemicalReaction {
+	return rt.reaction
+}
+
+func (rt *ReactionThermo) sum(property func(ThermoData) float64) float64 {
+	total := 0.0
+	for i, d := range rt.data {
+		total += rt.coefs[i] * property(d)
+	}
+	return total
+}
+
+func (rt *ReactionThermo) deltaG(temperature float64) float64 {
+	return rt.sum(func(d ThermoData) float64 { return d.Gibbs(temperature) })
+}
+
+func (rt *ReactionThermo) DeltaCp(temperature float64) float64 {
+	return utils.RoundFloat(rt.sum(func(d ThermoData) float64 { return d.HeatCapacity(temperature) }), rt.precision)
+}
+
+func (rt *ReactionThermo) DeltaH(temperature float64) float64 {
+	return utils.RoundFloat(rt.sum(func(d ThermoData) float64 { return d.Enthalpy(temperature) }), rt.precision)
+}
+
+func (rt *ReactionThermo) DeltaS(temperature float64) float64 {
+	return utils.RoundFloat(rt.sum(func(d ThermoData) float64 { return d.Entropy(temperature) }), rt.precision)
+}
+
+func (rt *ReactionThermo) DeltaG(temperature float64) float64 {
+	return utils.RoundFloat(rt.deltaG(temperature), rt.precision)
+}
+
+func (rt *ReactionThermo) InversionTemperature(tMin float64, tMax float64) (float64, bool) {
+	if tMin <= 0 || tMax <= tMin {
+		return 0, false
+	}
+	steps := int(math.Ceil(tMax - tMin))
+	low, gLow := tMin, rt.deltaG(tMin)
+	for i := 1; i <= steps; i++ {
+		high := math.Min(tMin+float64(i), tMax)
+		gHigh := rt.deltaG(high)
+		if gLow == 0 {
+			return utils.RoundFloat(low, rt.precision), true
+		}
+		if math.Signbit(gLow) != math.Signbit(gHigh) {
+			for high-low > 1e-9 {
+				mid := (low + high) / 2
+				gMid := rt.deltaG(mid)
+				if math.Signbit(gMid) == math.Signbit(gLow) {
+					low, gLow = mid, gMid
+				} else {
+					high = mid
+				}
+			}
+			return utils.RoundFloat((low+high)/2, rt.precision), true
+		}
+		low, gLow = high, gHigh
+	}
+	return 0, false
+}
+
+func (rt *ReactionThermo) Output(printPrecision ...uint) (rtOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	final, err := rt.reaction.FinalReaction()
+	if err != nil {
+		return rtOutput{}, err
+	}
+	inversion, found := rt.InversionTemperature(standardTemperature, 3000)
+	phases := make([]string, len(rt.data))
+	for i, d := range rt.data {
+		phases[i] = d.Phase
+	}
+
+	return rtOutput{
+		FinalReaction:        final,
+		Phases:               phases,
+		DeltaH:               utils.RoundFloat(rt.DeltaH(standardTemperature), pPrecision),
+		DeltaS:               utils.RoundFloat(rt.DeltaS(standardTemperature), pPrecision),
+		DeltaG:               utils.RoundFloat(rt.DeltaG(standardTemperature), pPrecision),
+		InversionTemperature: utils.RoundFloat(inversion, pPrecision),
+		HasInversion:         found,
+	}, nil
+}
+
+type rtOutput struct {
+	FinalReaction        string
+	Phases               []string
+	DeltaH               float64
+	DeltaS               float64
+	DeltaG               float64
+	InversionTemperature float64
+	HasInversion         bool
+}
+
+func (o rtOutput) String() string {
+	out := fmt.Sprintln("final reaction:", o.FinalReaction) +
+		fmt.Sprintln("phases:", o.Phases) +
+		fmt.Sprintf("ΔH°(298.15 K) = %v kJ\n", o.DeltaH) +
+		fmt.Sprintf("ΔS°(298.15 K) = %v J/K\n", o.DeltaS) +
+		fmt.Sprintf("ΔG°(298.15 K) = %v kJ\n", o.DeltaG)
+	if o.HasInversion {
+		return out + fmt.Sprintf("ΔG° changes sign at %v K", o.InversionTemperature)
+	}
+	return out + "ΔG° doesn't change sign in 298.15-3000 K"
+}
@@ -0,0 +1,138 @@
+package chemreaction
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const testThermoJSON = `[
+	{"formula": "CaCO3", "phase": "s", "hf": -1207.6, "s": 91.7},
+	{"formula": "CaO", "phase": "s", "hf": -634.9, "s": 38.1},
+	{"formula": "CO2", "phase": "g", "hf": -393.5, "s": 213.8},
+	{"formula": "H2O", "phase": "l", "hf": -285.83, "s": 69.95, "cp": [75.3]},
+	{"formula": "H2O", "phase": "g", "hf": -241.83, "s": 188.84, "cp": [33.6]}
+]`
+
+const testThermoCSV = `formula,phase,hf,s,a,b,c,d
+CaCO3,s,-1207.6,91.7,,,,
+CaO,s,-634.9,38.1,,,,
+CO2,g,-393.5,213.8,,,,
+H2O,l,-285.83,69.95,75.3,,,
+H2O,g,-241.83,188.84,33.6,,,
+`
+
+func TestThermoData(t *testing.T) {
+	d := ThermoData{Formula: "X", Hf: -100, S: 50, Cp: []float64{30}}
+	m := ThermoData{Formula: "Fe", S: 27.3, Cp: []float64{40}, Tm: 1811, Hm: 13.8, CpLiquid: []float64{46}}
+	tests := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"Cp", d.HeatCapacity(500), 30},
+		{"H(298.15)", d.Enthalpy(standardTemperature), -100},
+		{"H(398.15)", d.Enthalpy(398.15), -97},
+		{"S(398.15)", d.Entropy(398.15), 50 + 30*math.Log(398.15/298.15)},
+		{"G(298.15)", d.Gibbs(standardTemperature), -100 - standardTemperature*0.05},
+		{"liquid Cp", m.HeatCapacity(2000), 46},
+		{"H(1811)", m.Enthalpy(1811), 40 * (1811 - standardTemperature) / 1000},
+		{"H(2000)", m.Enthalpy(2000), 40*(1811-standardTemperature)/1000 + 13.8 + 46*189.0/1000},
+		{"S(2000)", m.Entropy(2000), 27.3 + 40*math.Log(1811/standardTemperature) + 13800/1811.0 + 46*math.Log(2000/1811.0)},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.expected) > 1e-9 {
+			t.Errorf("%s = %v, expected %v", tt.name, tt.got, tt.expected)
+		}
+	}
+}
+
+func TestReadThermoTable(t *testing.T) {
+	fromJSON, err := ReadThermoJSON(strings.NewReader(testThermoJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromCSV, err := ReadThermoCSV(strings.NewReader(testThermoCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []*ThermoTable{fromJSON, fromCSV} {
+		if len(table.Data()) != 5 {
+			t.Fatalf("expected 5 entries, got %d", len(table.Data()))
+		}
+		d, err := table.Lookup("H2O", "g")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Hf != -241.83 || d.HeatCapacity(400) != 33.6 {
+			t.Errorf("wrong H2O(g) data: %+v", d)
+		}
+		_, err = table.Lookup("H2O", "")
+		if err == nil {
+			t.Error("expected an error for an ambiguous phase")
+		}
+		_, err = table.Lookup("BaO", "")
+		if err == nil {
+			t.Error("expected an error for a missing compound")
+		}
+	}
+
+	_, err = NewThermoTable([]ThermoData{{Formula: "CaO", Phase: "s"}, {Formula: "CaO", Phase: "s"}})
+	if err == nil {
+		t.Error("expected an error for duplicate data")
+	}
+	_, err = ReadThermoCSV(strings.NewReader("formula,phase,hf\nCaO,s,-634.9\n"))
+	if err == nil {
+		t.Error("expected an error for a missing column")
+	}
+}
+
+func TestReactionThermo(t *testing.T) {
+	table, err := ReadThermoJSON(strings.NewReader(testThermoJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reaction, err := NewChemicalReaction("CaCO3=CaO+CO2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt, err := NewReactionThermo(reaction, table, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dh := rt.DeltaH(standardTemperature); math.Abs(dh-179.2) > 1e-6 {
+		t.Errorf("DeltaH() = %v, expected 179.2", dh)
+	}
+	if ds := rt.DeltaS(standardTemperature); math.Abs(ds-160.2) > 1e-6 {
+		t.Errorf("DeltaS() = %v, expected 160.2", ds)
+	}
+	if dg := rt.DeltaG(standardTemperature); math.Abs(dg-(179.2-standardTemperature*0.1602)) > 1e-6 {
+		t.Errorf("DeltaG() = %v", dg)
+	}
+	inversion, found := rt.InversionTemperature(standardTemperature, 3000)
+	if !found || math.Abs(inversion-179.2/0.1602) > 1e-6 {
+		t.Errorf("InversionTemperature() = %v, %v, expected %v", inversion, found, 179.2/0.1602)
+	}
+	_, found = rt.InversionTemperature(standardTemperature, 1000)
+	if found {
+		t.Error("expected no inversion below 1000 K")
+	}
+
+	out, err := rt.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "ΔG° changes sign at 1118.6017 K") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	hydrate, err := NewChemicalReaction("CaO+H2O=Ca(OH)2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewReactionThermo(hydrate, table, map[string]string{"H2O": "l"})
+	if err == nil {
+		t.Error("expected an error for missing Ca(OH)2 data")
+	}
+}
@@ -0,0 +1,204 @@
+package chemreaction
+
+import (
+	"fmt"
+
+	"github.com/Syrov-Egor/gosynthcalc/internal/utils"
+)
+
+type Titration struct {
+	reaction           *ChemicalReaction
+	analyte            int
+	titrant            int
+	titrantEquivalents float64
+	precision          uint
+}
+
+func NewTitration(reaction string, analyte int, titrant int, titrantEquivalents float64, options ...ReacOptions) (*Titration, error) {
+	reac, err := NewChemicalReaction(reaction, options...)
+	if err != nil {
+		return nil, err
+	}
+	if titrantEquivalents <= 0 {
+		return nil, fmt.Errorf("equivalents %v per mole of titrant should be > 0", titrantEquivalents)
+	}
+
+	indexes := make([]int, 2)
+	for i, index := range []int{analyte, titrant} {
+		low := -len(reac.decomposer.reactants)
+		high := len(reac.decomposer.products) - 1
+		if index < low || index > high {
+			return nil, fmt.Errorf("the compound integer %d should be in range %d : %d", index, low, high)
+		}
+		indexes[i] = index - low
+	}
+	if indexes[0] == indexes[1] {
+		return nil, fmt.Errorf("analyte and titrant should be different compounds")
+	}
+
+	return &Titration{
+		reaction:           reac,
+		analyte:            indexes[0],
+		titrant:            indexes[1],
+		titrantEquivalents: titrantEquivalents,
+		precision:          reac.reacOpts.Precision,
+	}, nil
+}
+
+func (t *Titration) Reaction() *ChemicalReaction {
+	return t.reaction
+}
+
+func (t *Titration) Analyte() string {
+	return t.reaction.decomposer.compounds[t.analyte]
+}
+
+func (t *Titration) Titrant() string {
+	return t.reaction.decomposer.compounds[t.titrant]
+}
+
+func (t *Titration) ratio() (float64, error) {
+	coefs, err := t.reaction.Coefficients()
+	if err != nil {
+		return 0, err
+	}
+	return coefs.Result[t.analyte] / coefs.Result[t.titrant], nil
+}
+
+func (t *Titration) analyteMolar() (float64, error) {
+	molars, err := t.reaction.MolarMasses()
+	if err != nil {
+		return 0, err
+	}
+	return molars[t.analyte], nil
+}
+
+func (t *Titration) Ratio() (float64, error) {
+	ratio, err := t.ratio()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(ratio, t.precision), nil
+}
+
+func (t *Titration) Equivalents() (float64, error) {
+	ratio, err := t.ratio()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(t.titrantEquivalents/ratio, t.precision), nil
+}
+
+func (t *Titration) EquivalentWeight() (float64, error) {
+	ratio, err := t.ratio()
+	if err != nil {
+		return 0, err
+	}
+	molar, err := t.analyteMolar()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(molar*ratio/t.titrantEquivalents, t.precision), nil
+}
+
+func (t *Titration) TitrantNormality(molarity float64) float64 {
+	return utils.RoundFloat(molarity*t.titrantEquivalents, t.precision)
+}
+
+func (t *Titration) AnalyteConcentration(titrantMolarity float64, titrantVolume float64, aliquot float64) (float64, error) {
+	if aliquot <= 0 {
+		return 0, fmt.Errorf("aliquot volume %v should be > 0", aliquot)
+	}
+	ratio, err := t.ratio()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(titrantMolarity*titrantVolume*ratio/aliquot, t.precision), nil
+}
+
+func (t *Titration) AnalyteNormality(titrantMolarity float64, titrantVolume float64, aliquot float64) (float64, error) {
+	if aliquot <= 0 {
+		return 0, fmt.Errorf("aliquot volume %v should be > 0", aliquot)
+	}
+	return utils.RoundFloat(titrantMolarity*t.titrantEquivalents*titrantVolume/aliquot, t.precision), nil
+}
+
+func (t *Titration) AnalyteMass(titrantMolarity float64, titrantVolume float64) (float64, error) {
+	ratio, err := t.ratio()
+	if err != nil {
+		return 0, err
+	}
+	molar, err := t.analyteMolar()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(titrantMolarity*titrantVolume/1000*ratio*molar, t.precision), nil
+}
+
+func (t *Titration) TitrantVolume(analyteMass float64, titrantMolarity float64) (float64, error) {
+	if titrantMolarity <= 0 {
+		return 0, fmt.Errorf("titrant concentration %v should be > 0", titrantMolarity)
+	}
+	ratio, err := t.ratio()
+	if err != nil {
+		return 0, err
+	}
+	molar, err := t.analyteMolar()
+	if err != nil {
+		return 0, err
+	}
+	return utils.RoundFloat(analyteMass/molar/ratio/titrantMolarity*1000, t.precision), nil
+}
+
+func (t *Titration) Output(printPrecision ...uint) (tiOutput, error) {
+	var pPrecision uint
+	if printPrecision == nil {
+		pPrecision = 4
+	} else {
+		pPrecision = printPrecision[0]
+	}
+
+	final, err := t.reaction.FinalReaction()
+	if err != nil {
+		return tiOutput{}, err
+	}
+	ratio, err := t.Ratio()
+	if err != nil {
+		return tiOutput{}, err
+	}
+	equivalents, err := t.Equivalents()
+	if err != nil {
+		return tiOutput{}, err
+	}
+	weight, err := t.EquivalentWeight()
+	if err != nil {
+		return tiOutput{}, err
+	}
+
+	return tiOutput{
+		FinalReaction:    final,
+		Analyte:          t.Analyte(),
+		Titrant:          t.Titrant(),
+		Ratio:            utils.RoundFloat(ratio, pPrecision),
+		Equivalents:      utils.RoundFloat(equivalents, pPrecision),
+		EquivalentWeight: utils.RoundFloat(weight, pPrecision),
+	}, nil
+}
+
+type tiOutput struct {
+	FinalReaction    string
+	Analyte          string
+	Titrant          string
+	Ratio            float64
+	Equivalents      float64
+	EquivalentWeight float64
+}
+
+func (o tiOutput) String() string {
+	return fmt.Sprintln("final reaction:", o.FinalReaction) +
+		fmt.Sprintln("analyte:", o.Analyte) +
+		fmt.Sprintln("titrant:", o.Titrant) +
+		fmt.Sprintf("mol of analyte per mol of titrant: %v\n", o.Ratio) +
+		fmt.Sprintf("equivalents per mol of analyte: %v\n", o.Equivalents) +
+		fmt.Sprintf("equivalent weight of analyte: %v g/eq", o.EquivalentWeight)
+}
@@ -0,0 +1,80 @@
+package chemreaction
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTitration(t *testing.T) {
+	tests := []struct {
+		name        string
+		reaction    string
+		analyte     int
+		titrant     int
+		equivalents float64
+		ratio       float64
+		analyteEq   float64
+		weight      float64
+	}{
+		{"acid-base", "H2SO4+NaOH=Na2SO4+H2O", -2, -1, 1, 0.5, 2, 49.0355},
+		{"permanganatometry", "KMnO4+FeSO4+H2SO4=K2SO4+MnSO4+Fe2(SO4)3+H2O", -2, -3, 5, 5, 1, 151.901},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ti, err := NewTitration(tt.reaction, tt.analyte, tt.titrant, tt.equivalents)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ratio, err := ti.Ratio()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ratio != tt.ratio {
+				t.Errorf("Ratio() = %v, expected %v", ratio, tt.ratio)
+			}
+			eq, _ := ti.Equivalents()
+			if eq != tt.analyteEq {
+				t.Errorf("Equivalents() = %v, expected %v", eq, tt.analyteEq)
+			}
+			weight, _ := ti.EquivalentWeight()
+			if math.Abs(weight-tt.weight) > 1e-3 {
+				t.Errorf("EquivalentWeight() = %v, expected %v", weight, tt.weight)
+			}
+		})
+	}
+}
+
+func TestTitration_concentrations(t *testing.T) {
+	ti, err := NewTitration("H2SO4+NaOH=Na2SO4+H2O", -2, -1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, _ := ti.AnalyteConcentration(0.1, 20, 10)
+	n, _ := ti.AnalyteNormality(0.1, 20, 10)
+	if c != 0.1 || n != 0.2 {
+		t.Errorf("AnalyteConcentration() = %v, AnalyteNormality() = %v, expected 0.1, 0.2", c, n)
+	}
+	m, _ := ti.AnalyteMass(0.1, 20)
+	if math.Abs(m-0.098072) > 1e-6 {
+		t.Errorf("AnalyteMass() = %v, expected 0.098072", m)
+	}
+	v, _ := ti.TitrantVolume(m, 0.1)
+	if math.Abs(v-20) > 1e-6 {
+		t.Errorf("TitrantVolume() = %v, expected 20", v)
+	}
+	if got := ti.TitrantNormality(0.05); got != 0.05 {
+		t.Errorf("TitrantNormality() = %v, expected 0.05", got)
+	}
+}
+
+func TestNewTitration_invalid(t *testing.T) {
+	if _, err := NewTitration("H2SO4+NaOH=Na2SO4+H2O", -3, -1, 1); err == nil {
+		t.Error("expected error for out of range analyte")
+	}
+	if _, err := NewTitration("H2SO4+NaOH=Na2SO4+H2O", -1, -1, 1); err == nil {
+		t.Error("expected error for the same analyte and titrant")
+	}
+	if _, err := NewTitration("H2SO4+NaOH=Na2SO4+H2O", -2, -1, 0); err == nil {
+		t.Error("expected error for zero equivalents")
+	}
+}
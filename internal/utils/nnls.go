@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func NNLS(a *mat.Dense, b []float64, tol float64) ([]float64, float64, error) {
+	rows, cols := a.Dims()
+	if len(b) != rows {
+		return nil, 0, fmt.Errorf("length of vector b should be %d, got %d", rows, len(b))
+	}
+
+	x := make([]float64, cols)
+	passive := make([]bool, cols)
+	blocked := make([]bool, cols)
+	bVec := mat.NewVecDense(rows, b)
+
+	eps := math.Nextafter(1, 2) - 1
+	gradTol := math.Max(tol, 10*eps*mat.Norm(a, 1)*float64(max(rows, cols)))
+	maxIter := 3*cols + 1
+	converged := false
+	for iter := 0; iter < maxIter; iter++ {
+		w := nnlsGradient(a, x, bVec)
+		j := -1
+		for i := range cols {
+			if !passive[i] && !blocked[i] && w[i] > gradTol && (j < 0 || w[i] > w[j]) {
+				j = i
+			}
+		}
+		if j < 0 {
+			converged = true
+			break
+		}
+		passive[j] = true
+
+		for inner := 0; ; inner++ {
+			if inner == maxIter {
+				return nil, 0, fmt.Errorf("NNLS inner loop did not converge in %d iterations", maxIter)
+			}
+			z, err := nnlsSubsetSolve(a, bVec, passive)
+			if err != nil {
+				return nil, 0, err
+			}
+			if inner == 0 && z[j] <= tol {
+				passive[j] = false
+				blocked[j] = true
+				break
+			}
+			feasible := true
+			for i := range cols {
+				if passive[i] && z[i] <= tol {
+					feasible = false
+				}
+			}
+			if feasible {
+				x = z
+				clear(blocked)
+				break
+			}
+
+			alpha := math.Inf(1)
+			k := -1
+			for i := range cols {
+				if passive[i] && z[i] <= tol && x[i] != z[i] && x[i]/(x[i]-z[i]) < alpha {
+					alpha = x[i] / (x[i] - z[i])
+					k = i
+				}
+			}
+			if k < 0 {
+				return nil, 0, fmt.Errorf("NNLS step is undefined")
+			}
+			for i := range cols {
+				x[i] += alpha * (z[i] - x[i])
+				if passive[i] && (i == k || math.Abs(x[i]) <= tol) {
+					passive[i] = false
+					x[i] = 0
+				}
+			}
+		}
+	}
+
+	if !converged {
+		return nil, 0, fmt.Errorf("NNLS did not converge in %d iterations", maxIter)
+	}
+	return x, nnlsResidual(a, x, bVec), nil
+}
+
+func nnlsGradient(a *mat.Dense, x []float64, b *mat.VecDense) []float64 {
+	_, cols := a.Dims()
+	var r, w mat.VecDense
+	r.MulVec(a, mat.NewVecDense(cols, x))
+	r.SubVec(b, &r)
+	w.MulVec(a.T(), &r)
+	return w.RawVector().Data
+}
+
+func nnlsResidual(a *mat.Dense, x []float64, b *mat.VecDense) float64 {
+	_, cols := a.Dims()
+	var r mat.VecDense
+	r.MulVec(a, mat.NewVecDense(cols, x))
+	r.SubVec(b, &r)
+	return mat.Norm(&r, 2)
+}
+
+func nnlsSubsetSolve(a *mat.Dense, b *mat.VecDense, passive []bool) ([]float64, error) {
+	rows, cols := a.Dims()
+	idx := []int{}
+	for i, p := range passive {
+		if p {
+			idx = append(idx, i)
+		}
+	}
+
+	sub := mat.NewDense(rows, len(idx), nil)
+	for j, col := range idx {
+		for i := range rows {
+			sub.Set(i, j, a.At(i, col))
+		}
+	}
+
+	var svd mat.SVD
+	if !svd.Factorize(sub, mat.SVDThin) {
+		return nil, fmt.Errorf("SVD factorization failed")
+	}
+	values := svd.Values(nil)
+	rank := 0
+	for _, val := range values {
+		if val > 1e-12*values[0] {
+			rank++
+		}
+	}
+
+	var sol mat.Dense
+	svd.SolveTo(&sol, b, rank)
+
+	z := make([]float64, cols)
+	for j, col := range idx {
+		z[col] = sol.At(j, 0)
+	}
+	return z, nil
+}
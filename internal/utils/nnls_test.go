@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestNNLS(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        *mat.Dense
+		b        []float64
+		expected []float64
+		residual float64
+	}{
+		{
+			name:     "exact positive solution",
+			a:        mat.NewDense(2, 2, []float64{1, 0, 0, 2}),
+			b:        []float64{3, 4},
+			expected: []float64{3, 2},
+			residual: 0,
+		},
+		{
+			name:     "negative component clipped",
+			a:        mat.NewDense(2, 2, []float64{1, 0, 0, 1}),
+			b:        []float64{2, -1},
+			expected: []float64{2, 0},
+			residual: 1,
+		},
+		{
+			name:     "overdetermined",
+			a:        mat.NewDense(3, 2, []float64{1, 0, 0, 1, 1, 1}),
+			b:        []float64{1, 1, 2},
+			expected: []float64{1, 1},
+			residual: 0,
+		},
+		{
+			name:     "mixed sources",
+			a:        mat.NewDense(2, 3, []float64{1, 0, 0.5, 0, 1, 0.5}),
+			b:        []float64{0.7, 0.3},
+			expected: []float64{0.7, 0.3, 0},
+			residual: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, res, err := NNLS(tt.a, tt.b, 1e-10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range x {
+				if math.Abs(x[i]-tt.expected[i]) > 1e-8 {
+					t.Errorf("NNLS() = %v, want %v", x, tt.expected)
+					break
+				}
+			}
+			if math.Abs(res-tt.residual) > 1e-8 {
+				t.Errorf("NNLS() residual = %v, want %v", res, tt.residual)
+			}
+		})
+	}
+}
+
+func TestNNLSWrongLength(t *testing.T) {
+	_, _, err := NNLS(mat.NewDense(2, 2, nil), []float64{1}, 1e-10)
+	if err == nil {
+		t.Errorf("NNLS() should fail on wrong vector length")
+	}
+}
+
+func TestNNLSDegenerateColumn(t *testing.T) {
+	a := mat.NewDense(2, 3, []float64{
+		0, 2, 0,
+		2, 2, 2.0000000000004,
+	})
+	for _, tol := range []float64{0, 1e-12} {
+		x, res, err := NNLS(a, []float64{2, 2}, tol)
+		if err != nil {
+			t.Fatalf("NNLS() error = %v", err)
+		}
+		for _, v := range x {
+			if math.IsNaN(v) || v < 0 {
+				t.Errorf("NNLS() = %v, should be finite and non-negative", x)
+			}
+		}
+		if res > 1e-9 {
+			t.Errorf("NNLS() residual = %v, want 0", res)
+		}
+	}
+}